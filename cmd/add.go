@@ -5,10 +5,16 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"syscall"
 
+	"github.com/hungnguyen18/uzp-cli/internal/generator"
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
+)
+
+var (
+	addMeta     secretMeta
+	addGenerate string
+	addGen      generatorFlags
 )
 
 var addCmd = &cobra.Command{
@@ -30,14 +36,42 @@ ORGANIZATION:
     myapp/database_url
     backend/jwt_secret
 
+When stdin is not a terminal, project, key, value and the update
+confirmation are read line by line from stdin. For scripts, prefer
+'uzp set'.
+
+METADATA:
+  --type totp          TOTP seed or otpauth:// URI (see uzp otp)
+  --note TEXT          Note stored with the secret
+  --tag TAG            Tag (repeatable or comma-separated)
+  --url URL            URL of the service
+  --expires DATE       Expiry date (YYYY-MM-DD) or interval from now (90d)
+  --rotate-every 90d   Rotation interval (see uzp due)
+
+GENERATE:
+  --generate[=MODE]    Generate the value instead of prompting for it
+                       (password, passphrase, hex, base64, uuid); takes
+                       the options of uzp generate, e.g. --length 32
+
 EXAMPLES:
   uzp add                 Interactive mode
+  uzp add --tag prod --note "Rotated by ops"
+  uzp add --generate --length 32 --no-symbols
 
   Project name: myapp
   Key name: api_key
   Value (hidden): 
   Added: myapp/api_key`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		if err := addMeta.validate(); err != nil {
+			return err
+		}
+		genOpts, err := addGen.generateOption(cmd, addGenerate)
+		if err != nil {
+			return err
+		}
+
 		// Check if vault is unlocked, prompt for password if needed
 		if err := ensureVaultUnlocked(); err != nil {
 			return err
@@ -62,12 +96,18 @@ EXAMPLES:
 		key = strings.TrimSpace(key)
 
 		// Get value (sensitive, so use password input)
-		fmt.Print("Value (hidden): ")
-		valueBytes, err := term.ReadPassword(int(syscall.Stdin))
-		if err != nil {
-			return fmt.Errorf("failed to read value: %w", err)
+		var valueBytes []byte
+		if genOpts != nil {
+			valueBytes, err = generator.Generate(*genOpts)
+			if err != nil {
+				return err
+			}
+		} else {
+			valueBytes, err = readHidden(reader, "Value (hidden): ")
+			if err != nil {
+				return fmt.Errorf("failed to read value: %w", err)
+			}
 		}
-		fmt.Println() // New line after password
 		value := string(valueBytes)
 
 		// Validate inputs
@@ -104,7 +144,11 @@ EXAMPLES:
 		}
 
 		// Add/Update to vault
-		if err := vault.Add(project, key, value); err != nil {
+		err = vault.Put(project, key, func(secret *storage.Secret) error {
+			secret.Value = value
+			return addMeta.apply(cmd, secret)
+		})
+		if err != nil {
 			if isUpdate {
 				return fmt.Errorf("failed to update secret: %w", err)
 			}
@@ -125,3 +169,8 @@ EXAMPLES:
 		return nil
 	},
 }
+
+func init() {
+	addMeta.addFlags(addCmd)
+	addGen.addGenerateFlags(addCmd, &addGenerate)
+}
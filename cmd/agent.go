@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/hungnguyen18/uzp-cli/internal/agent"
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	agentSocket      string
+	agentForeground  bool
+	agentIdleTimeout time.Duration
+	agentMaxLifetime time.Duration
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Start the background unlock agent",
+	Long: `Unlock Agent
+
+Start an ssh-agent style background process that keeps derived vault keys
+in memory, so commands do not prompt for the master password every time.
+The agent listens on a Unix socket readable only by you.
+
+EXAMPLES:
+  eval "$(uzp agent)"          Start the agent and export UZP_AGENT_SOCK
+  uzp unlock                   Unlock the current vault in the agent
+  uzp get myapp/api_key        No password prompt while unlocked
+  uzp lock                     Forget the key
+  uzp agent status             Show unlocked vaults
+  uzp agent stop               Stop the agent and wipe all keys
+
+TIMEOUTS:
+  --idle-timeout   Forget a key after this long without use (default: 15m)
+  --max-lifetime   Forget a key this long after unlock, even if in use (default: 4h)
+
+NOTE:
+  Commands only use the agent when UZP_AGENT_SOCK is set.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		socketPath, err := agentSocketPath()
+		if err != nil {
+			return err
+		}
+
+		if agentIdleTimeout <= 0 || agentMaxLifetime <= 0 {
+			return fmt.Errorf("timeouts must be positive")
+		}
+
+		if agentForeground {
+			return runAgent(socketPath)
+		}
+
+		return startAgent(socketPath)
+	},
+}
+
+var agentStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show vaults unlocked in the agent",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := requireAgent()
+		if err != nil {
+			return err
+		}
+
+		vaults, err := client.Status()
+		if err != nil {
+			return err
+		}
+
+		if len(vaults) == 0 {
+			fmt.Println("Agent is running. No vaults unlocked.")
+			return nil
+		}
+
+		for _, v := range vaults {
+			remaining := time.Until(v.ExpiresAt).Round(time.Second)
+			fmt.Printf("%s (locks in %s)\n", v.Path, remaining)
+		}
+
+		return nil
+	},
+}
+
+var agentStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the agent and wipe all keys",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := requireAgent()
+		if err != nil {
+			return err
+		}
+
+		if err := client.Stop(); err != nil {
+			return err
+		}
+
+		fmt.Println("Agent stopped.")
+		return nil
+	},
+}
+
+func init() {
+	agentCmd.Flags().StringVar(&agentSocket, "socket", "", "Socket path (default: $UZP_AGENT_SOCK or ~/.uzp/agent.sock)")
+	agentCmd.Flags().BoolVar(&agentForeground, "foreground", false, "Run in the foreground instead of detaching")
+	agentCmd.Flags().DurationVar(&agentIdleTimeout, "idle-timeout", 15*time.Minute, "Forget a key after this long without use")
+	agentCmd.Flags().DurationVar(&agentMaxLifetime, "max-lifetime", 4*time.Hour, "Forget a key this long after unlock")
+
+	agentCmd.AddCommand(agentStatusCmd)
+	agentCmd.AddCommand(agentStopCmd)
+}
+
+// agentSocketPath returns the socket from --socket, UZP_AGENT_SOCK or the data directory
+func agentSocketPath() (string, error) {
+	path := agentSocket
+	if path == "" {
+		path = os.Getenv(agent.SocketEnv)
+	}
+	if path == "" {
+		dir, err := storage.DataDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(dir, "agent.sock")
+	}
+	return filepath.Abs(path)
+}
+
+// runAgent serves requests until the agent is stopped or signalled
+func runAgent(socketPath string) error {
+	server := agent.NewServer(socketPath, agentIdleTimeout, agentMaxLifetime)
+	if err := server.Listen(); err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		server.Stop()
+	}()
+
+	return server.Serve()
+}
+
+// startAgent launches a detached agent and prints shell commands to use it
+func startAgent(socketPath string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate uzp executable: %w", err)
+	}
+
+	child := exec.Command(executable, "agent", "--foreground",
+		"--socket", socketPath,
+		"--idle-timeout", agentIdleTimeout.String(),
+		"--max-lifetime", agentMaxLifetime.String())
+	child.SysProcAttr = detachedProcAttr()
+
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("failed to start agent: %w", err)
+	}
+	pid := child.Process.Pid
+	_ = child.Process.Release()
+
+	// Wait until the agent accepts connections
+	client := agent.NewClient(socketPath)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := client.Status(); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("agent did not start on %s", socketPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// Shell commands for eval, like ssh-agent
+	fmt.Printf("%s=%s; export %s;\n", agent.SocketEnv, strconv.Quote(socketPath), agent.SocketEnv)
+	fmt.Printf("echo Agent pid %d;\n", pid)
+
+	return nil
+}
+
+// requireAgent returns a client for UZP_AGENT_SOCK or explains how to start one
+func requireAgent() (*agent.Client, error) {
+	client := agent.ClientFromEnv()
+	if client == nil {
+		return nil, fmt.Errorf("%s is not set. Start an agent with: eval \"$(uzp agent)\"", agent.SocketEnv)
+	}
+	return client, nil
+}
@@ -46,10 +46,12 @@ OPTIONS:
 		key := parts[1]
 
 		// Get value
-		value, err := vault.Get(project, key)
+		secret, err := vault.GetSecret(project, key)
 		if err != nil {
 			return err
 		}
+		warnIfDue(project, key, secret)
+		value := secret.Value
 
 		// Copy to clipboard
 		duration := time.Duration(ttl) * time.Second
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	cpProjectMode bool
+	cpForce       bool
+)
+
+var cpCmd = &cobra.Command{
+	Use:   "cp <project/key> <project/key>",
+	Short: "Duplicate a secret or project",
+	Long: `Duplicate Secret
+
+Copy a secret under a new name, or duplicate a whole project.
+The source is left untouched.
+
+FORMAT:
+  project/key project/key
+  -p source_project new_project
+
+EXAMPLES:
+  uzp cp backend/db_url worker/db_url   Duplicate secret into another project
+  uzp cp -p myapp myapp-staging         Duplicate project
+
+OPTIONS:
+  -p, --project  Copy a whole project instead of a single secret
+  -f, --force    Overwrite existing secrets at the destination
+
+NOTE:
+  To copy a value to the clipboard, use 'uzp copy'.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTransfer(args, cpProjectMode, cpForce, false)
+	},
+}
+
+func init() {
+	cpCmd.Flags().BoolVarP(&cpProjectMode, "project", "p", false, "Copy a whole project")
+	cpCmd.Flags().BoolVarP(&cpForce, "force", "f", false, "Overwrite existing secrets at the destination")
+}
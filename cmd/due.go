@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// ErrSecretsDue is returned by 'uzp due --check' when secrets need rotation;
+// main maps it to its exit status without printing it
+var ErrSecretsDue = errors.New("secrets are due for rotation")
+
+var (
+	dueWithin string
+	dueCheck  bool
+)
+
+var dueCmd = &cobra.Command{
+	Use:   "due",
+	Short: "List secrets that are expired or due for rotation soon",
+	Long: `Due Secrets
+
+List secrets across all projects that are past their expiry or rotation
+date, or will be within --within (default 14d). Set dates with --expires
+and --rotate-every on add, update or set.
+
+EXAMPLES:
+  uzp due
+  uzp due --within 30d
+  uzp due --check            Exit with status 7 if anything is due (for cron)
+  uzp due -o json
+
+OUTPUT:
+  STATUS   SECRET          DUE AT                   IN
+  expired  aws/access_key  2024-05-01 00:00:00 UTC  3 days ago
+  soon     stripe/api_key  2024-05-10 00:00:00 UTC  in 6 days
+
+  With --output json|yaml|table|plain, one record per secret with the
+  fields project, key, status, due_at, expires_at and rotate_every.
+
+OPTIONS:
+  --within INTERVAL   Also list secrets due within this interval (e.g. 14d, 2w)
+  --check             Exit with status 7 when any secret is listed`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		within, err := storage.ParseInterval(dueWithin)
+		if err != nil {
+			return err
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		projects, err := vault.List()
+		if err != nil {
+			return err
+		}
+
+		type dueSecret struct {
+			project, key string
+			secret       storage.Secret
+			due          time.Time
+		}
+
+		current := time.Now()
+		var found []dueSecret
+		for project, keys := range projects {
+			for _, key := range keys {
+				secret, err := vault.GetSecret(project, key)
+				if err != nil {
+					return err
+				}
+				due, ok := secret.DueAt()
+				if ok && due.Before(current.Add(within)) {
+					found = append(found, dueSecret{project, key, secret, due})
+				}
+			}
+		}
+
+		// Most urgent first
+		sort.Slice(found, func(i, j int) bool {
+			if !found[i].due.Equal(found[j].due) {
+				return found[i].due.Before(found[j].due)
+			}
+			return found[i].project+"/"+found[i].key < found[j].project+"/"+found[j].key
+		})
+
+		records := make([]outputRecord, 0, len(found))
+		for _, f := range found {
+			status := "soon"
+			if !f.due.After(current) {
+				status = "expired"
+			}
+
+			if outputFormat == "" {
+				records = append(records, outputRecord{
+					{Name: "status", Value: status},
+					{Name: "secret", Value: f.project + "/" + f.key},
+					{Name: "due at", Value: formatTime(f.due)},
+					{Name: "in", Value: describeDue(f.due)},
+				})
+				continue
+			}
+
+			records = append(records, outputRecord{
+				{Name: "project", Value: f.project},
+				{Name: "key", Value: f.key},
+				{Name: "status", Value: status},
+				{Name: "due_at", Value: formatTimestamp(f.due)},
+				{Name: "expires_at", Value: formatTimestampPtr(f.secret.ExpiresAt)},
+				{Name: "rotate_every", Value: f.secret.RotateEvery},
+			})
+		}
+
+		if outputFormat != "" {
+			columns := []string{"project", "key", "status", "due_at", "expires_at", "rotate_every"}
+			if err := writeRecords(os.Stdout, outputFormat, columns, records); err != nil {
+				return err
+			}
+		} else if len(records) == 0 {
+			fmt.Printf("No secrets due within %s.\n", dueWithin)
+		} else {
+			if err := writeRecords(os.Stdout, outputTable, []string{"status", "secret", "due at", "in"}, records); err != nil {
+				return err
+			}
+		}
+
+		if dueCheck && len(found) > 0 {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			return ErrSecretsDue
+		}
+		return nil
+	},
+}
+
+func init() {
+	dueCmd.Flags().StringVar(&dueWithin, "within", "14d", "Also list secrets due within this interval")
+	dueCmd.Flags().BoolVar(&dueCheck, "check", false, "Exit with status 7 when any secret is listed")
+}
+
+// describeDue describes a due date relative to now, e.g. "in 3 days"
+func describeDue(due time.Time) string {
+	d := time.Until(due)
+	past := d < 0
+	if past {
+		d = -d
+	}
+
+	var amount string
+	switch {
+	case d >= 48*time.Hour:
+		amount = fmt.Sprintf("%d days", int(d.Hours()/24))
+	case d >= 2*time.Hour:
+		amount = fmt.Sprintf("%d hours", int(d.Hours()))
+	default:
+		amount = fmt.Sprintf("%d minutes", int(d.Minutes()))
+	}
+
+	if past {
+		return amount + " ago"
+	}
+	return "in " + amount
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Output formats supported by inject
+const (
+	formatDotenv     = "dotenv"
+	formatShell      = "shell"
+	formatFish       = "fish"
+	formatPowerShell = "powershell"
+	formatJSON       = "json"
+	formatYAML       = "yaml"
+	formatDocker     = "docker"
+	formatSystemd    = "systemd"
+)
+
+// envFormats lists the supported formats in help order
+var envFormats = []string{
+	formatDotenv, formatShell, formatFish, formatPowerShell,
+	formatJSON, formatYAML, formatDocker, formatSystemd,
+}
+
+// envVar is a single environment variable derived from a secret
+type envVar struct {
+	Name  string
+	Value string
+}
+
+// writeEnv writes vars in the given format, with a comment header where the format allows it
+func writeEnv(w io.Writer, format, project string, vars []envVar) error {
+	var buf bytes.Buffer
+
+	header := func(comment string) {
+		fmt.Fprintf(&buf, "%s Environment variables for project: %s\n", comment, project)
+		fmt.Fprintf(&buf, "%s Generated by uzp\n\n", comment)
+	}
+
+	switch format {
+	case formatDotenv:
+		header("#")
+		for _, v := range vars {
+			fmt.Fprintf(&buf, "%s=%s\n", v.Name, quoteDotenv(v.Value))
+		}
+
+	case formatShell:
+		header("#")
+		for _, v := range vars {
+			fmt.Fprintf(&buf, "export %s=%s\n", v.Name, quoteShell(v.Value))
+		}
+
+	case formatFish:
+		header("#")
+		for _, v := range vars {
+			fmt.Fprintf(&buf, "set -gx %s %s\n", v.Name, quoteFish(v.Value))
+		}
+
+	case formatPowerShell:
+		header("#")
+		for _, v := range vars {
+			fmt.Fprintf(&buf, "$env:%s = %s\n", v.Name, quotePowerShell(v.Value))
+		}
+
+	case formatJSON:
+		buf.WriteString("{")
+		for i, v := range vars {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			fmt.Fprintf(&buf, "\n  %s: %s", quoteJSON(v.Name), quoteJSON(v.Value))
+		}
+		if len(vars) > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+
+	case formatYAML:
+		header("#")
+		for _, v := range vars {
+			// YAML double-quoted scalars accept JSON string escapes; keys are
+			// quoted too so names like ON or NULL stay strings
+			fmt.Fprintf(&buf, "%s: %s\n", quoteJSON(v.Name), quoteJSON(v.Value))
+		}
+
+	case formatDocker:
+		header("#")
+		for _, v := range vars {
+			// docker --env-file takes values literally and has no multiline syntax
+			if strings.ContainsAny(v.Value, "\r\n") {
+				return fmt.Errorf("%s contains a newline, which docker env files cannot represent", v.Name)
+			}
+			fmt.Fprintf(&buf, "%s=%s\n", v.Name, v.Value)
+		}
+
+	case formatSystemd:
+		header("#")
+		for _, v := range vars {
+			fmt.Fprintf(&buf, "%s=%s\n", v.Name, quoteSystemd(v.Value))
+		}
+
+	default:
+		return fmt.Errorf("unsupported format: %s (use one of: %s)", format, strings.Join(envFormats, ", "))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// isEnvFormat reports whether format is a supported output format
+func isEnvFormat(format string) bool {
+	for _, f := range envFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlainValue reports whether s needs no quoting in any line-based format
+func isPlainValue(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			continue
+		}
+		if !strings.ContainsRune("_-./:@,+%=", rune(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteDotenv quotes a value for .env files. Single quotes are literal in
+// dotenv parsers; double quotes are used when the value contains a single
+// quote or a newline, escaping backslashes, quotes, newlines and $ (which
+// docker compose, godotenv and python-dotenv would otherwise expand).
+func quoteDotenv(s string) string {
+	if isPlainValue(s) {
+		return s
+	}
+	if !strings.ContainsAny(s, "'\r\n") {
+		return "'" + s + "'"
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`, "\r", `\r`)
+	return `"` + replacer.Replace(s) + `"`
+}
+
+// quoteShell quotes a value for POSIX shells using single quotes
+func quoteShell(s string) string {
+	if isPlainValue(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteFish quotes a value for fish, where only \ and ' are special in single quotes
+func quoteFish(s string) string {
+	if isPlainValue(s) {
+		return s
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, "'", `\'`)
+	return "'" + replacer.Replace(s) + "'"
+}
+
+// quotePowerShell quotes a value as a PowerShell verbatim string
+func quotePowerShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteSystemd quotes a value for systemd EnvironmentFile, which follows
+// shell double-quote rules
+func quoteSystemd(s string) string {
+	if isPlainValue(s) {
+		return s
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+	return `"` + replacer.Replace(s) + `"`
+}
+
+// quoteJSON encodes s as a JSON string without HTML escaping
+func quoteJSON(s string) string {
+	return encodeJSON(s)
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hungnguyen18/uzp-cli/internal/dotenv"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// trickyVars are values that need quoting or escaping in at least one format
+var trickyVars = []envVar{
+	{Name: "PLAIN", Value: "abc-123_./:@,+%="},
+	{Name: "SPACES", Value: "hello world  "},
+	{Name: "HASH", Value: "value #not-a-comment"},
+	{Name: "DOLLAR", Value: "$HOME and ${PATH}"},
+	{Name: "SINGLE_QUOTE", Value: "it's"},
+	{Name: "DOUBLE_QUOTE", Value: `say "hi"`},
+	{Name: "BACKSLASH", Value: `C:\path\to\n`},
+	{Name: "BACKTICK", Value: "`whoami`"},
+	{Name: "NEWLINE", Value: "line1\nline2"},
+	{Name: "CRLF", Value: "line1\r\nline2"},
+	{Name: "UNICODE", Value: "héllo wörld ✓ 日本"},
+	{Name: "MIXED", Value: "it's $HOME \"x\" #y\nline2"},
+}
+
+func TestWriteEnvGolden(t *testing.T) {
+	for _, format := range envFormats {
+		t.Run(format, func(t *testing.T) {
+			vars := trickyVars
+			if format == formatDocker {
+				// docker env files cannot hold newlines, see TestWriteEnvDockerNewline
+				vars = nil
+				for _, v := range trickyVars {
+					if !strings.ContainsAny(v.Value, "\r\n") {
+						vars = append(vars, v)
+					}
+				}
+			}
+
+			var buf bytes.Buffer
+			if err := writeEnv(&buf, format, "myapp", vars); err != nil {
+				t.Fatalf("writeEnv: %v", err)
+			}
+
+			golden := filepath.Join("testdata", "inject", format+".golden")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden file (run go test ./cmd -update to create it): %v", err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("output differs from %s\ngot:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+func TestWriteEnvDockerNewline(t *testing.T) {
+	err := writeEnv(&bytes.Buffer{}, formatDocker, "myapp", []envVar{{Name: "NEWLINE", Value: "a\nb"}})
+	if err == nil {
+		t.Fatal("expected an error for a value with a newline")
+	}
+}
+
+func TestDotenvRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeEnv(&buf, formatDotenv, "myapp", trickyVars); err != nil {
+		t.Fatalf("writeEnv: %v", err)
+	}
+
+	entries, err := dotenv.Parse(&buf)
+	if err != nil {
+		t.Fatalf("parse output: %v", err)
+	}
+	if len(entries) != len(trickyVars) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(trickyVars))
+	}
+	for i, entry := range entries {
+		want := trickyVars[i]
+		if entry.Key != want.Name || entry.Value != want.Value {
+			t.Errorf("entry %d = %s=%q, want %s=%q", i, entry.Key, entry.Value, want.Name, want.Value)
+		}
+	}
+}
+
+func TestQuoteDotenvEscapesDollar(t *testing.T) {
+	got := quoteDotenv("it's $HOME \"x\" #y\nline2")
+	want := `"it's \$HOME \"x\" #y\nline2"`
+	if got != want {
+		t.Errorf("quoteDotenv = %s, want %s", got, want)
+	}
+}
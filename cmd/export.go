@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportProject     string
+	exportK8sName     string
+	exportK8sNS       string
+	exportServiceName string
+	exportComposeMode string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Generate deployment manifests from a project",
+	Long: `Export Manifests
+
+Render a project's secrets as deployment manifests.
+
+EXAMPLES:
+  uzp export k8s -p myapp --name myapp-secrets --namespace prod | kubectl apply -f -
+  uzp export compose -p myapp --service web > docker-compose.override.yml
+  uzp export compose -p myapp --mode secrets
+
+NOTE:
+  Keys are converted to UPPERCASE with underscores, as in 'uzp inject'.
+  Manifests contain secret values (base64 is not encryption); avoid
+  committing them to version control.`,
+}
+
+var exportK8sCmd = &cobra.Command{
+	Use:   "k8s",
+	Short: "Render a Kubernetes v1 Secret",
+	Long: `Export Kubernetes Secret
+
+Render a project's secrets as a Kubernetes v1 Secret with base64 data.
+
+EXAMPLES:
+  uzp export k8s -p myapp
+  uzp export k8s -p myapp --name myapp-secrets --namespace prod
+  uzp export k8s -p myapp | kubectl apply -f -
+
+OPTIONS:
+  --name       Secret name (default: project name)
+  --namespace  Namespace (omitted if not set)`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		if exportProject == "" {
+			return fmt.Errorf("missing project name\n\nusage: uzp export k8s -p PROJECT_NAME")
+		}
+
+		name := exportK8sName
+		if name == "" {
+			name = k8sName(exportProject)
+		}
+		if !isK8sName(name) {
+			return fmt.Errorf("invalid secret name %q: use lowercase letters, digits, '-' and '.'", name)
+		}
+		if exportK8sNS != "" && !isK8sLabel(exportK8sNS) {
+			return fmt.Errorf("invalid namespace %q: use at most 63 lowercase letters, digits and '-'", exportK8sNS)
+		}
+
+		vars, err := exportProjectEnv()
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		buf.WriteString("apiVersion: v1\n")
+		buf.WriteString("kind: Secret\n")
+		buf.WriteString("metadata:\n")
+		fmt.Fprintf(&buf, "  name: %s\n", name)
+		if exportK8sNS != "" {
+			fmt.Fprintf(&buf, "  namespace: %s\n", exportK8sNS)
+		}
+		buf.WriteString("  labels:\n")
+		buf.WriteString("    app.kubernetes.io/managed-by: uzp\n")
+		buf.WriteString("type: Opaque\n")
+		if len(vars) == 0 {
+			buf.WriteString("data: {}\n")
+		} else {
+			buf.WriteString("data:\n")
+			for _, v := range vars {
+				// Keys are quoted so names like ON or NULL stay strings
+				fmt.Fprintf(&buf, "  %s: %s\n", quoteJSON(v.Name), base64.StdEncoding.EncodeToString([]byte(v.Value)))
+			}
+		}
+
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	},
+}
+
+var exportComposeCmd = &cobra.Command{
+	Use:   "compose",
+	Short: "Render a docker-compose snippet",
+	Long: `Export docker-compose Snippet
+
+Render a project's secrets as a docker-compose snippet.
+
+MODES (--mode):
+  environment  Values inline under services.<service>.environment (default)
+  secrets      Compose secrets sourced from host environment variables,
+               without any values; combine with 'uzp run':
+               uzp run -p myapp -- docker compose up
+
+EXAMPLES:
+  uzp export compose -p myapp --service web
+  uzp export compose -p myapp --mode secrets`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		if exportProject == "" {
+			return fmt.Errorf("missing project name\n\nusage: uzp export compose -p PROJECT_NAME")
+		}
+		if exportComposeMode != "environment" && exportComposeMode != "secrets" {
+			return fmt.Errorf("unsupported mode: %s (use environment or secrets)", exportComposeMode)
+		}
+
+		service := exportServiceName
+		if service == "" {
+			service = exportProject
+		}
+
+		vars, err := exportProjectEnv()
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		buf.WriteString("services:\n")
+		fmt.Fprintf(&buf, "  %s:\n", quoteJSON(service))
+
+		if exportComposeMode == "environment" {
+			buf.WriteString("    environment:\n")
+			for _, v := range vars {
+				// Compose interpolates $ in values; $$ is a literal dollar
+				fmt.Fprintf(&buf, "      %s: %s\n", quoteJSON(v.Name), quoteJSON(strings.ReplaceAll(v.Value, "$", "$$")))
+			}
+		} else {
+			buf.WriteString("    secrets:\n")
+			for _, v := range vars {
+				fmt.Fprintf(&buf, "      - %s\n", strings.ToLower(v.Name))
+			}
+			buf.WriteString("secrets:\n")
+			for _, v := range vars {
+				fmt.Fprintf(&buf, "  %s:\n", strings.ToLower(v.Name))
+				fmt.Fprintf(&buf, "    environment: %s\n", v.Name)
+			}
+		}
+
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	},
+}
+
+func init() {
+	exportCmd.PersistentFlags().StringVarP(&exportProject, "project", "p", "", "Project name to export secrets from")
+
+	exportK8sCmd.Flags().StringVar(&exportK8sName, "name", "", "Secret name (default: project name)")
+	exportK8sCmd.Flags().StringVarP(&exportK8sNS, "namespace", "n", "", "Namespace of the secret")
+
+	exportComposeCmd.Flags().StringVar(&exportServiceName, "service", "", "Service name (default: project name)")
+	exportComposeCmd.Flags().StringVar(&exportComposeMode, "mode", "environment", "Snippet style: environment or secrets")
+
+	exportCmd.AddCommand(exportK8sCmd)
+	exportCmd.AddCommand(exportComposeCmd)
+}
+
+// exportProjectEnv unlocks the vault and returns the export project's variables
+func exportProjectEnv() ([]envVar, error) {
+	// Check if vault is unlocked, prompt for password if needed
+	if err := ensureVaultUnlocked(); err != nil {
+		return nil, err
+	}
+
+	secrets, err := vault.GetProjectSecrets(exportProject)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exporting %d secrets from project '%s'\n", len(secrets), exportProject)
+	return projectEnv(secrets), nil
+}
+
+// k8sName converts a project name to a valid Kubernetes object name
+func k8sName(project string) string {
+	result := make([]byte, 0, len(project))
+	for i := 0; i < len(project); i++ {
+		c := project[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			result = append(result, c+32)
+		case (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '.':
+			result = append(result, c)
+		default:
+			result = append(result, '-')
+		}
+	}
+	return strings.Trim(string(result), "-.")
+}
+
+// isK8sName reports whether name is a valid RFC 1123 subdomain
+func isK8sName(name string) bool {
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '.') {
+			return false
+		}
+	}
+	first, last := name[0], name[len(name)-1]
+	return first != '-' && first != '.' && last != '-' && last != '.'
+}
+
+// isK8sLabel reports whether name is a valid RFC 1123 label, as required
+// for namespaces
+func isK8sLabel(name string) bool {
+	if name == "" || len(name) > 63 {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-') {
+			return false
+		}
+	}
+	return name[0] != '-' && name[len(name)-1] != '-'
+}
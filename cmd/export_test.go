@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsK8sName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"myapp":                  true,
+		"myapp-secrets":          true,
+		"my.app.v2":              true,
+		strings.Repeat("a", 253): true,
+		"":                       false,
+		strings.Repeat("a", 254): false,
+		"MyApp":                  false,
+		"my_app":                 false,
+		"-myapp":                 false,
+		"myapp.":                 false,
+		"my app":                 false,
+	} {
+		if got := isK8sName(name); got != want {
+			t.Errorf("isK8sName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestIsK8sLabel(t *testing.T) {
+	for name, want := range map[string]bool{
+		"default":               true,
+		"team-a":                true,
+		"1st":                   true,
+		strings.Repeat("a", 63): true,
+		"":                      false,
+		strings.Repeat("a", 64): false,
+		"team.a":                false,
+		"Team":                  false,
+		"team_a":                false,
+		"-team":                 false,
+		"team-":                 false,
+	} {
+		if got := isK8sLabel(name); got != want {
+			t.Errorf("isK8sLabel(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hungnguyen18/uzp-cli/internal/generator"
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/hungnguyen18/uzp-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	generateMode  string
+	generatePrint bool
+	generateCopy  bool
+	generateTTL   int
+	generateYes   bool
+	generateGen   generatorFlags
+	generateMeta  secretMeta
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate [project/key]",
+	Short: "Generate a random password or token",
+	Long: `Generate Secret
+
+Generate a random password, passphrase or token with crypto/rand. With a
+project/key the value is written straight into the vault and never shown;
+without one it is printed (or copied with --copy).
+
+EXAMPLES:
+  uzp generate                                   Print a 24-character password
+  uzp generate myapp/db_password                 Store a new password
+  uzp generate myapp/db_password -y --copy       Rotate it and copy the new value
+  uzp generate --length 16 --no-symbols --no-ambiguous
+  uzp generate --mode passphrase --words 5       e.g. cubicle-dorsal-nutmeg-...
+  uzp generate myapp/session_key --mode hex      64 hex characters (32 bytes)
+  uzp generate myapp/api_token --mode base64 --length 48
+  uzp generate --mode uuid
+
+MODES:
+  password     Letters, digits and symbols (default, 24 characters)
+  passphrase   Words from the EFF large word list (default 6 words)
+  hex          Random bytes as hex (default 32 bytes)
+  base64       Random bytes as URL-safe base64 without padding (default 32 bytes)
+  uuid         Random version 4 UUID
+
+OPTIONS:
+  -m, --mode MODE            Generation mode (see MODES)
+  -l, --length N             Characters for passwords, bytes for hex and base64
+                             (at most 1024)
+  --words N                  Words for passphrases (at most 100)
+  --separator SEP            Between passphrase words (default "-")
+  --no-upper, --no-lower     Leave out a character class
+  --no-digits, --no-symbols
+  --no-ambiguous             Leave out look-alike characters (0 O o 1 l I |)
+  --print                    Also print the stored value
+  --copy                     Copy the value to the clipboard
+  -y, --yes                  Overwrite an existing secret without confirmation
+  --note, --tag, --url       Metadata stored with the secret
+  --expires, --rotate-every  Expiry and rotation reminders (see uzp due)
+
+NOTE:
+  Overwriting a secret keeps the old value in its history (see uzp
+  history), so a rotation can be undone with uzp rollback.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		var project, key string
+		if len(args) == 1 {
+			var err error
+			project, key, err = parseSecretPath(args[0])
+			if err != nil {
+				return err
+			}
+		} else if generateMeta.changed(cmd) {
+			return fmt.Errorf("metadata flags need a secret to store: uzp generate project/key")
+		}
+		if err := generateMeta.validate(); err != nil {
+			return err
+		}
+
+		opts, err := generateGen.options(cmd, generateMode)
+		if err != nil {
+			return err
+		}
+
+		valueBytes, err := generator.Generate(opts)
+		if err != nil {
+			return err
+		}
+		defer clearBytes(valueBytes)
+
+		// Without a secret to store, asking for a value means showing it
+		if len(args) == 0 {
+			if generateCopy {
+				return copyGenerated(string(valueBytes))
+			}
+			fmt.Println(string(valueBytes))
+			return nil
+		}
+		value := string(valueBytes)
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		// Check if key already exists
+		_, err = vault.Get(project, key)
+		isUpdate := err == nil
+
+		if isUpdate && !generateYes {
+			if !stdinIsTerminal() {
+				return fmt.Errorf("secret '%s/%s' %w; pass --yes to overwrite", project, key, storage.ErrExists)
+			}
+
+			ok, err := confirm(bufio.NewReader(os.Stdin), fmt.Sprintf("Secret '%s/%s' already exists. Replace with a new value? (y/N): ", project, key))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+		}
+
+		err = vault.Put(project, key, func(secret *storage.Secret) error {
+			secret.Value = value
+			return generateMeta.apply(cmd, secret)
+		})
+		if err != nil {
+			if isUpdate {
+				return fmt.Errorf("failed to update secret: %w", err)
+			}
+			return fmt.Errorf("failed to add secret: %w", err)
+		}
+
+		if isUpdate {
+			fmt.Printf("Updated: %s/%s (generated %s)\n", project, key, describeGenerated(opts, valueBytes))
+		} else {
+			fmt.Printf("Added: %s/%s (generated %s)\n", project, key, describeGenerated(opts, valueBytes))
+		}
+
+		if generatePrint {
+			fmt.Println(value)
+		}
+		if generateCopy {
+			return copyGenerated(value)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	generateCmd.Flags().StringVarP(&generateMode, "mode", "m", generator.ModePassword, "Generation mode: "+strings.Join(generator.Modes, ", "))
+	generateCmd.Flags().BoolVar(&generatePrint, "print", false, "Also print the stored value")
+	generateCmd.Flags().BoolVar(&generateCopy, "copy", false, "Copy the value to the clipboard")
+	generateCmd.Flags().IntVarP(&generateTTL, "ttl", "t", 15, "Time to live in seconds before clipboard is cleared")
+	generateCmd.Flags().BoolVarP(&generateYes, "yes", "y", false, "Overwrite an existing secret without confirmation")
+	generateGen.addFlags(generateCmd)
+	generateMeta.addFlags(generateCmd)
+}
+
+// generatorFlags holds the options shared by generate and --generate on
+// add and update
+type generatorFlags struct {
+	length      int
+	words       int
+	separator   string
+	noUpper     bool
+	noLower     bool
+	noDigits    bool
+	noSymbols   bool
+	noAmbiguous bool
+}
+
+// addFlags registers the generator options on cmd
+func (g *generatorFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&g.length, "length", "l", 0, "Characters for passwords, random bytes for hex and base64 (default: 24, 32)")
+	cmd.Flags().IntVar(&g.words, "words", 0, "Words for passphrases (default: 6)")
+	cmd.Flags().StringVar(&g.separator, "separator", "-", "Separator between passphrase words")
+	cmd.Flags().BoolVar(&g.noUpper, "no-upper", false, "Leave out uppercase letters")
+	cmd.Flags().BoolVar(&g.noLower, "no-lower", false, "Leave out lowercase letters")
+	cmd.Flags().BoolVar(&g.noDigits, "no-digits", false, "Leave out digits")
+	cmd.Flags().BoolVar(&g.noSymbols, "no-symbols", false, "Leave out symbols")
+	cmd.Flags().BoolVar(&g.noAmbiguous, "no-ambiguous", false, "Leave out look-alike characters (0 O o 1 l I |)")
+}
+
+// options returns the generator options for mode, rejecting flags that
+// do not apply to it
+func (g *generatorFlags) options(cmd *cobra.Command, mode string) (generator.Options, error) {
+	opts := generator.DefaultOptions()
+	opts.Mode = mode
+	opts.Separator = g.separator
+
+	flags := cmd.Flags()
+	passwordOnly := []string{"no-upper", "no-lower", "no-digits", "no-symbols", "no-ambiguous"}
+	passphraseOnly := []string{"words", "separator"}
+
+	var ignored []string
+	switch mode {
+	case generator.ModePassword:
+		opts.Length = g.length
+		opts.Upper = !g.noUpper
+		opts.Lower = !g.noLower
+		opts.Digits = !g.noDigits
+		opts.Symbols = !g.noSymbols
+		opts.ExcludeAmbiguous = g.noAmbiguous
+		ignored = passphraseOnly
+	case generator.ModePassphrase:
+		opts.Length = g.words
+		ignored = append([]string{"length"}, passwordOnly...)
+	case generator.ModeHex, generator.ModeBase64:
+		opts.Length = g.length
+		ignored = append(passwordOnly, passphraseOnly...)
+	case generator.ModeUUID:
+		ignored = append([]string{"length"}, append(passwordOnly, passphraseOnly...)...)
+	default:
+		return opts, fmt.Errorf("unsupported mode: %s (use one of: %s)", mode, strings.Join(generator.Modes, ", "))
+	}
+
+	for _, name := range ignored {
+		if flags.Changed(name) {
+			return opts, fmt.Errorf("--%s does not apply to %s mode", name, mode)
+		}
+	}
+
+	return opts, opts.Validate()
+}
+
+// generateOption returns the generator options for --generate on add and
+// update, or nil when it was not given
+func (g *generatorFlags) generateOption(cmd *cobra.Command, mode string) (*generator.Options, error) {
+	if !cmd.Flags().Changed("generate") {
+		for _, name := range []string{"length", "words", "separator", "no-upper", "no-lower", "no-digits", "no-symbols", "no-ambiguous"} {
+			if cmd.Flags().Changed(name) {
+				return nil, fmt.Errorf("--%s needs --generate", name)
+			}
+		}
+		return nil, nil
+	}
+
+	opts, err := g.options(cmd, mode)
+	if err != nil {
+		return nil, err
+	}
+	return &opts, nil
+}
+
+// addGenerateFlags registers --generate[=MODE] and the generator options
+func (g *generatorFlags) addGenerateFlags(cmd *cobra.Command, mode *string) {
+	cmd.Flags().StringVar(mode, "generate", "", "Generate the value instead of prompting: "+strings.Join(generator.Modes, ", "))
+	cmd.Flags().Lookup("generate").NoOptDefVal = generator.ModePassword
+	g.addFlags(cmd)
+}
+
+// describeGenerated summarizes a generated value without revealing it
+func describeGenerated(opts generator.Options, value []byte) string {
+	switch opts.Mode {
+	case generator.ModePassphrase:
+		words := opts.Length
+		if words == 0 {
+			words = generator.DefaultWords
+		}
+		return fmt.Sprintf("%d-word passphrase", words)
+	case generator.ModeUUID:
+		return "UUID"
+	default:
+		return fmt.Sprintf("%s, %d characters", opts.Mode, len(value))
+	}
+}
+
+// copyGenerated copies a generated value to the clipboard
+func copyGenerated(value string) error {
+	if err := utils.CopyToClipboard(value, time.Duration(generateTTL)*time.Second); err != nil {
+		return err
+	}
+	fmt.Printf("Copied to clipboard; it will be cleared in %d seconds.\n", generateTTL)
+	return nil
+}
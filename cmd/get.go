@@ -2,11 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+var getNoNewline bool
+
 var getCmd = &cobra.Command{
 	Use:   "get <project/key>",
 	Short: "Get a secret value from the vault",
@@ -20,7 +23,14 @@ FORMAT:
 EXAMPLES:
   uzp get myapp/api_key
   uzp get backend/database_url
-  uzp get auth/jwt_secret`,
+  uzp get auth/jwt_secret
+  uzp get myapp/tls_key --no-newline > key.pem
+  uzp get myapp/api_key -o json
+
+OUTPUT:
+  By default (and with -o plain) the value is printed as is, followed by
+  a newline unless --no-newline is set. With -o json|yaml|table a record
+  with the fields project, key and value is printed.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Validate arguments FIRST before prompting for password
@@ -43,14 +53,32 @@ EXAMPLES:
 		key := parts[1]
 
 		// Get value
-		value, err := vault.Get(project, key)
+		secret, err := vault.GetSecret(project, key)
 		if err != nil {
-			return fmt.Errorf("secret not found: %s/%s", project, key)
+			return err
 		}
+		warnIfDue(project, key, secret)
+		value := secret.Value
 
-		// Print value
-		fmt.Println(value)
-
-		return nil
+		switch outputFormat {
+		case "", outputPlain:
+			// Print value
+			fmt.Print(value)
+			if !getNoNewline {
+				fmt.Println()
+			}
+			return nil
+		default:
+			record := outputRecord{
+				{Name: "project", Value: project},
+				{Name: "key", Value: key},
+				{Name: "value", Value: value},
+			}
+			return writeRecords(os.Stdout, outputFormat, []string{"project", "key", "value"}, []outputRecord{record})
+		}
 	},
 }
+
+func init() {
+	getCmd.Flags().BoolVarP(&getNoNewline, "no-newline", "n", false, "Do not print a trailing newline")
+}
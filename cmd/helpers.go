@@ -1,31 +1,458 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/hungnguyen18/uzp-cli/internal/agent"
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/hungnguyen18/uzp-cli/internal/totp"
+	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
-// ensureVaultUnlocked checks if vault is unlocked and prompts for password if needed
+// ensureVaultUnlocked checks if vault is unlocked and prompts for password if needed.
+// When UZP_AGENT_SOCK is set, a key held by the agent is used instead of prompting.
 func ensureVaultUnlocked() error {
-	if !vault.IsUnlocked() {
-		fmt.Fprint(os.Stderr, "Enter master password: ")
-		password, err := term.ReadPassword(int(syscall.Stdin))
+	if !vault.IsUnlocked() && !unlockFromAgent() {
+		password, err := unlockVault()
 		if err != nil {
-			return fmt.Errorf("failed to read password: %w", err)
+			return err
 		}
-		fmt.Fprintln(os.Stderr) // New line after password
 
-		if err := vault.Unlock(string(password)); err != nil {
-			return fmt.Errorf("invalid password")
+		// Clear password from memory
+		clearBytes(password)
+	}
+	return nil
+}
+
+// unlockVault reads the master password and unlocks the vault.
+// The password is returned for commands that need it again (e.g. to
+// re-derive the key); the caller is responsible for clearing it.
+func unlockVault() ([]byte, error) {
+	password, err := readMasterPassword()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := vault.Unlock(string(password)); err != nil {
+		clearBytes(password)
+		return nil, err
+	}
+
+	if vault.Recovered() {
+		fmt.Fprintln(os.Stderr, "Warning: vault file was corrupt and has been restored from backup.")
+		if damaged := vault.DamagedPath(); damaged != "" {
+			fmt.Fprintf(os.Stderr, "The damaged file was kept at %s.\n", damaged)
 		}
+	}
 
-		// Clear password from memory
-		for i := range password {
-			password[i] = 0
+	return password, nil
+}
+
+// readMasterPassword reads the master password from the first configured
+// source: --password-fd, --password-file, UZP_PASSWORD, UZP_ASKPASS, and
+// finally the terminal. The caller is responsible for clearing it.
+func readMasterPassword() ([]byte, error) {
+	switch {
+	case passwordFD >= 0:
+		// stdin stays open for the command (e.g. set --value-stdin)
+		if passwordFD == 0 {
+			return readPasswordLine(os.Stdin)
+		}
+
+		f := os.NewFile(uintptr(passwordFD), "password-fd")
+		if f == nil {
+			return nil, fmt.Errorf("invalid --password-fd: %d", passwordFD)
+		}
+		defer f.Close()
+		return readPasswordLine(f)
+
+	case passwordFile != "":
+		f, err := os.Open(passwordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open password file: %w", err)
+		}
+		defer f.Close()
+
+		if info, err := f.Stat(); err == nil && runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+			fmt.Fprintf(os.Stderr, "Warning: password file %s is accessible by other users (mode %04o).\n", passwordFile, info.Mode().Perm())
+		}
+		return readPasswordLine(f)
+
+	case os.Getenv("UZP_PASSWORD") != "":
+		fmt.Fprintln(os.Stderr, "Warning: using the master password from UZP_PASSWORD; environment variables can leak through process listings and child processes.")
+		password := []byte(os.Getenv("UZP_PASSWORD"))
+		// Keep it out of child processes (e.g. uzp run)
+		os.Unsetenv("UZP_PASSWORD")
+		return password, nil
+
+	case os.Getenv("UZP_ASKPASS") != "":
+		askpass := exec.Command(os.Getenv("UZP_ASKPASS"), "Enter master password: ")
+		askpass.Stderr = os.Stderr
+		output, err := askpass.Output()
+		if err != nil {
+			clearBytes(output)
+			return nil, fmt.Errorf("UZP_ASKPASS program failed: %w", err)
+		}
+		return trimNewline(output), nil
+	}
+
+	if !stdinIsTerminal() {
+		return nil, fmt.Errorf("%w: no terminal to prompt for the master password (use --password-fd, --password-file or the agent)", storage.ErrLocked)
+	}
+
+	fmt.Fprint(os.Stderr, "Enter master password: ")
+	password, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password: %w", err)
+	}
+	fmt.Fprintln(os.Stderr) // New line after password
+	return password, nil
+}
+
+// readPasswordLine reads the first line of r without buffering past it,
+// so the rest of the stream is left for the command
+func readPasswordLine(r io.Reader) ([]byte, error) {
+	// Preallocate so the buffer is not copied (and left behind) as it grows
+	password := make([]byte, 0, 256)
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			password = append(password, buf[0])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			clearBytes(password)
+			return nil, fmt.Errorf("failed to read password: %w", err)
+		}
+	}
+
+	password = bytes.TrimSuffix(password, []byte("\r"))
+	if len(password) == 0 {
+		return nil, fmt.Errorf("failed to read password: empty input")
+	}
+	return password, nil
+}
+
+// unlockFromAgent tries to unlock the vault with a key held by the agent
+func unlockFromAgent() bool {
+	client := agent.ClientFromEnv()
+	if client == nil {
+		return false
+	}
+
+	key, err := client.Get(vault.Path())
+	if err != nil {
+		return false
+	}
+	defer clearBytes(key)
+
+	return vault.UnlockWithKey(key) == nil
+}
+
+// refreshAgentKey replaces the agent's key after the vault was re-keyed,
+// if the agent was holding the old one
+func refreshAgentKey() {
+	client := agent.ClientFromEnv()
+	if client == nil {
+		return
+	}
+
+	oldKey, err := client.Get(vault.Path())
+	if err != nil {
+		return
+	}
+	clearBytes(oldKey)
+
+	key, err := vault.Key()
+	if err != nil {
+		return
+	}
+	defer clearBytes(key)
+
+	if err := client.Add(vault.Path(), key); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update agent key: %v\n", err)
+	}
+}
+
+// warnProjectDue warns about every secret of a project that is past due
+func warnProjectDue(project string, secrets map[string]string) {
+	keys := make([]string, 0, len(secrets))
+	for key := range secrets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if secret, err := vault.GetSecret(project, key); err == nil {
+			warnIfDue(project, key, secret)
+		}
+	}
+}
+
+// parseSecretPath splits a "project/key" argument into its parts
+func parseSecretPath(path string) (string, string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid format. use: project/key")
+	}
+	return parts[0], parts[1], nil
+}
+
+// validateProjectName rejects project names that could not be addressed
+// later as "project/key"
+func validateProjectName(name string) error {
+	if name == "" {
+		return fmt.Errorf("project names cannot be empty")
+	}
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("invalid project name %q: must not contain '/'", name)
+	}
+	return nil
+}
+
+// confirm asks a yes/no question and reports whether the user agreed
+func confirm(reader *bufio.Reader, prompt string) (bool, error) {
+	fmt.Print(prompt)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal
+func stdinIsTerminal() bool {
+	return term.IsTerminal(int(syscall.Stdin))
+}
+
+// readHidden reads a sensitive value. On a terminal the input is not
+// echoed; when stdin is a pipe the next line is read from reader, so the
+// same reader must be used for any later prompts. The caller is
+// responsible for clearing the returned buffer.
+func readHidden(reader *bufio.Reader, prompt string) ([]byte, error) {
+	if stdinIsTerminal() {
+		fmt.Print(prompt)
+		value, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println() // New line after password
+		return value, err
+	}
+
+	line, err := reader.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		clearBytes(line)
+		return nil, err
+	}
+	return trimNewline(line), nil
+}
+
+// trimNewline removes a single trailing "\n" or "\r\n"
+func trimNewline(b []byte) []byte {
+	b = bytes.TrimSuffix(b, []byte("\n"))
+	return bytes.TrimSuffix(b, []byte("\r"))
+}
+
+// promptNewPassword asks for a new password twice and checks its strength.
+// The caller is responsible for clearing the returned buffer.
+func promptNewPassword(label string) ([]byte, error) {
+	fmt.Printf("Enter %s: ", label)
+	password, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password: %w", err)
+	}
+	fmt.Println() // New line after password
+
+	// Confirm password
+	fmt.Printf("Confirm %s: ", label)
+	confirmPassword, err := term.ReadPassword(int(syscall.Stdin))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password: %w", err)
+	}
+	fmt.Println() // New line after password
+
+	// Check if passwords match
+	match := string(password) == string(confirmPassword)
+	clearBytes(confirmPassword)
+	if !match {
+		clearBytes(password)
+		return nil, fmt.Errorf("passwords do not match")
+	}
+
+	// Check password strength
+	if len(password) < 8 {
+		clearBytes(password)
+		return nil, fmt.Errorf("password must be at least 8 characters long")
+	}
+
+	return password, nil
+}
+
+// secretMeta holds the metadata flags of commands that write secrets
+type secretMeta struct {
+	secretType  string
+	note        string
+	tags        []string
+	url         string
+	expires     string
+	rotateEvery string
+}
+
+// addFlags registers --type, --note, --tag, --url, --expires and
+// --rotate-every on cmd
+func (m *secretMeta) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&m.secretType, "type", "", "Secret type: plain or totp (otpauth:// URI or base32 seed, see uzp otp)")
+	cmd.Flags().StringVar(&m.note, "note", "", "Note stored with the secret")
+	cmd.Flags().StringSliceVar(&m.tags, "tag", nil, "Tag for the secret (repeatable, replaces existing tags)")
+	cmd.Flags().StringVar(&m.url, "url", "", "URL of the service the secret belongs to")
+	cmd.Flags().StringVar(&m.expires, "expires", "", "Expiry date (YYYY-MM-DD, RFC 3339) or interval from now (e.g. 90d)")
+	cmd.Flags().StringVar(&m.rotateEvery, "rotate-every", "", "Rotation interval, e.g. 90d, 2w or 36h")
+}
+
+// changed reports whether any metadata flag was given
+func (m *secretMeta) changed(cmd *cobra.Command) bool {
+	flags := cmd.Flags()
+	for _, name := range []string{"type", "note", "tag", "url", "expires", "rotate-every"} {
+		if flags.Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the metadata flags before any prompt
+func (m *secretMeta) validate() error {
+	if _, err := parseSecretType(m.secretType); err != nil {
+		return err
+	}
+	if m.expires != "" {
+		if _, err := parseExpiry(m.expires); err != nil {
+			return err
+		}
+	}
+	if m.rotateEvery != "" {
+		if _, err := storage.ParseInterval(m.rotateEvery); err != nil {
+			return err
 		}
 	}
 	return nil
 }
+
+// apply sets the metadata given on the command line; an empty flag
+// value clears the field
+func (m *secretMeta) apply(cmd *cobra.Command, secret *storage.Secret) error {
+	flags := cmd.Flags()
+	if flags.Changed("type") {
+		secretType, err := parseSecretType(m.secretType)
+		if err != nil {
+			return err
+		}
+		secret.Type = secretType
+	}
+	if secret.Type == storage.TypeTOTP {
+		key, err := totp.Parse(secret.Value)
+		if err != nil {
+			return err
+		}
+		key.Clear()
+	}
+	if flags.Changed("expires") {
+		secret.ExpiresAt = nil
+		if m.expires != "" {
+			expires, err := parseExpiry(m.expires)
+			if err != nil {
+				return err
+			}
+			secret.ExpiresAt = &expires
+		}
+	}
+	if flags.Changed("rotate-every") {
+		secret.RotateEvery = m.rotateEvery
+	}
+	if flags.Changed("note") {
+		secret.Notes = m.note
+	}
+	if flags.Changed("url") {
+		secret.URL = m.url
+	}
+	if flags.Changed("tag") {
+		secret.Tags = nil
+		for _, tag := range m.tags {
+			tag = strings.TrimSpace(tag)
+			if tag != "" && !containsString(secret.Tags, tag) {
+				secret.Tags = append(secret.Tags, tag)
+			}
+		}
+	}
+	return nil
+}
+
+// parseSecretType parses the --type flag; "plain" and "" are the default type
+func parseSecretType(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "", "plain":
+		return storage.TypePlain, nil
+	case storage.TypeTOTP:
+		return storage.TypeTOTP, nil
+	default:
+		return "", fmt.Errorf("unsupported secret type: %s (use plain or totp)", s)
+	}
+}
+
+// parseExpiry parses an expiry date (YYYY-MM-DD in local time, or RFC 3339)
+// or an interval from now such as "90d"
+func parseExpiry(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	if interval, err := storage.ParseInterval(s); err == nil {
+		return time.Now().Add(interval).UTC().Truncate(time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry %q: use YYYY-MM-DD, an RFC 3339 time or an interval such as 90d", s)
+}
+
+// warnIfDue prints a warning on stderr when a secret is past its expiry
+// or rotation date
+func warnIfDue(project, key string, secret storage.Secret) {
+	due, ok := secret.DueAt()
+	if ok && !due.After(time.Now()) {
+		fmt.Fprintf(os.Stderr, "Warning: %s/%s was due for rotation on %s (see uzp due)\n", project, key, formatTime(due))
+	}
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// clearBytes overwrites sensitive data in memory
+func clearBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import "testing"
+
+func TestParseSecretPath(t *testing.T) {
+	project, key, err := parseSecretPath("myapp/api_key")
+	if err != nil || project != "myapp" || key != "api_key" {
+		t.Errorf("parseSecretPath = %q, %q, %v", project, key, err)
+	}
+
+	for _, path := range []string{"", "myapp", "/key", "myapp/", "a/b/c"} {
+		if _, _, err := parseSecretPath(path); err == nil {
+			t.Errorf("parseSecretPath(%q) succeeded, want an error", path)
+		}
+	}
+}
+
+func TestValidateProjectName(t *testing.T) {
+	if err := validateProjectName("myapp"); err != nil {
+		t.Errorf("validateProjectName(myapp) = %v", err)
+	}
+	for _, name := range []string{"", "a/b", "/", "myapp/"} {
+		if err := validateProjectName(name); err == nil {
+			t.Errorf("validateProjectName(%q) succeeded, want an error", name)
+		}
+	}
+}
+
+func TestRunTransferRejectsInvalidNamesBeforeUnlock(t *testing.T) {
+	// vault is nil here, so reaching the unlock step would panic
+	for _, tt := range []struct {
+		args        []string
+		projectMode bool
+	}{
+		{[]string{"src", "a/b"}, true},
+		{[]string{"a/b", "dst"}, true},
+		{[]string{"src", ""}, true},
+		{[]string{"src/key", "dst/a/b"}, false},
+		{[]string{"src/key", "dst"}, false},
+	} {
+		for _, move := range []bool{true, false} {
+			if err := runTransfer(tt.args, tt.projectMode, false, move); err == nil {
+				t.Errorf("runTransfer(%q, project=%v, move=%v) succeeded, want an error", tt.args, tt.projectMode, move)
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var historyReveal bool
+
+var historyCmd = &cobra.Command{
+	Use:   "history <project/key>",
+	Short: "List previous values of a secret",
+	Long: `Secret History
+
+List the previous values of a secret, newest first. Values are hidden
+unless --reveal is set. Restore one with 'uzp rollback'.
+
+EXAMPLES:
+  uzp history myapp/api_key
+  uzp history myapp/api_key --reveal
+  uzp history myapp/api_key -o json
+  uzp history retention          Show how many revisions are kept
+  uzp history retention 5        Keep 5 revisions per secret (0 disables)
+
+OUTPUT:
+  REV  SET AT                   REPLACED AT
+  0    2024-05-02 10:00:00 UTC  (current)
+  1    2024-04-01 09:30:00 UTC  2024-05-02 10:00:00 UTC
+
+  With --output json|yaml|table|plain, one record per revision with the
+  fields revision, set_at, replaced_at (and value with --reveal).
+
+NOTE:
+  Previous values stay in the (encrypted) vault until they fall out of
+  the retention limit or the secret is removed. After rotating a leaked
+  secret, lower the limit or remove and re-add the secret to drop them.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		project, key, err := parseSecretPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		secret, err := vault.GetSecret(project, key)
+		if err != nil {
+			return err
+		}
+
+		// The current value is revision 0
+		revisions := append([]storage.Revision{{Value: secret.Value, SetAt: secret.UpdatedAt}}, secret.History...)
+
+		if outputFormat != "" {
+			columns := []string{"revision", "set_at", "replaced_at"}
+			records := make([]outputRecord, 0, len(revisions))
+			for i, revision := range revisions {
+				record := outputRecord{
+					{Name: "revision", Value: i},
+					{Name: "set_at", Value: formatTimestamp(revision.SetAt)},
+					{Name: "replaced_at", Value: formatTimestamp(revision.ReplacedAt)},
+				}
+				records = append(records, withRevealedValue(record, revision.Value))
+			}
+			if historyReveal {
+				columns = append(columns, "value")
+			}
+			return writeRecords(os.Stdout, outputFormat, columns, records)
+		}
+
+		// Human-readable table in local time
+		columns := []string{"rev", "set at", "replaced at"}
+		rows := make([]outputRecord, 0, len(revisions))
+		for i, revision := range revisions {
+			replaced := "(current)"
+			if i > 0 {
+				replaced = formatTime(revision.ReplacedAt)
+			}
+			row := outputRecord{
+				{Name: "rev", Value: i},
+				{Name: "set at", Value: formatTime(revision.SetAt)},
+				{Name: "replaced at", Value: replaced},
+			}
+			rows = append(rows, withRevealedValue(row, revision.Value))
+		}
+		if historyReveal {
+			columns = append(columns, "value")
+		}
+
+		fmt.Printf("History of %s/%s:\n", project, key)
+		return writeRecords(os.Stdout, outputTable, columns, rows)
+	},
+}
+
+var historyRetentionCmd = &cobra.Command{
+	Use:   "retention [limit]",
+	Short: "Show or set how many previous values are kept per secret",
+	Long: `History Retention
+
+Show or set how many previous values are kept for each secret in this
+vault. Lowering the limit drops older revisions immediately; 0 keeps no
+history at all.
+
+EXAMPLES:
+  uzp history retention
+  uzp history retention 20
+  uzp history retention 0`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		limit := -1
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid limit %q: use a number of revisions (0 disables history)", args[0])
+			}
+			limit = n
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		if limit < 0 {
+			current, err := vault.HistoryLimit()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Keeping %d previous values per secret.\n", current)
+			return nil
+		}
+
+		if err := vault.SetHistoryLimit(limit); err != nil {
+			return fmt.Errorf("failed to set history limit: %w", err)
+		}
+
+		fmt.Printf("Now keeping %d previous values per secret.\n", limit)
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().BoolVar(&historyReveal, "reveal", false, "Also show the values")
+
+	historyCmd.AddCommand(historyRetentionCmd)
+}
+
+// withRevealedValue adds the value to record when --reveal is set
+func withRevealedValue(record outputRecord, value string) outputRecord {
+	if historyReveal {
+		record = append(record, outputField{Name: "value", Value: value})
+	}
+	return record
+}
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hungnguyen18/uzp-cli/internal/dotenv"
+	"github.com/hungnguyen18/uzp-cli/internal/importer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importProject   string
+	importDryRun    bool
+	importYes       bool
+	importLowercase bool
+	importFrom      string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import --from FORMAT <file>",
+	Short: "Import secrets from files or other password managers",
+	Long: `Import Secrets
+
+Import secrets from existing files or another password manager's export
+in a single vault write.
+
+EXAMPLES:
+  uzp import env -p myapp .env                          Import a dotenv file
+  uzp import env -p myapp .env --dry-run                Only show what would change
+  uzp import --from bitwarden-json bitwarden.json       Import a Bitwarden export
+  uzp import --from 1password-csv export.csv -p team    Import a 1Password export
+  uzp import --from keepass-xml keepass.xml --dry-run   Preview a KeePass import
+  uzp import --from pass-dir ~/.password-store          Import a pass store
+
+FORMATS:
+  bitwarden-json   Unencrypted JSON export; folders become projects
+  1password-csv    CSV export; a Vault column, if present, selects the project
+  keepass-xml      KeePass 2 XML export; groups become projects (nested: a-b)
+  pass-dir         pass(1) store; top-level directories become projects,
+                   files are decrypted with gpg; "name: value" lines become
+                   keys and otpauth:// lines TOTP secrets (ITEM_totp)
+
+Fields become keys named ITEM_FIELD (e.g. github_password, github_username).
+TOTP fields become ITEM_totp secrets of type totp for 'uzp otp'; values that
+are not a base32 seed or otpauth://totp/ URI are skipped.
+Items without a folder, vault or group go into the --project (default: the
+format's name, e.g. "bitwarden"). Skipped items are reported on stderr.
+
+OPTIONS:
+  --from           Export format (see FORMATS)
+  -p, --project    Project for items without a folder
+  --dry-run        Show the changes without writing
+  -y, --yes        Apply without confirmation`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		if importFrom == "" {
+			return cmd.Help()
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("missing export file\n\nusage: uzp import --from FORMAT FILE")
+		}
+
+		// Items without a folder default to the format's name (e.g. "bitwarden")
+		project := importProject
+		if project == "" {
+			project = strings.SplitN(importFrom, "-", 2)[0]
+		}
+
+		result, err := importer.Import(importFrom, args[0], project)
+		if err != nil {
+			return err
+		}
+
+		for _, reason := range result.Skipped {
+			fmt.Fprintf(os.Stderr, "Skipping %s\n", reason)
+		}
+
+		if len(result.Secrets) == 0 {
+			fmt.Println("No secrets found in export.")
+			return nil
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		return applyImport(result.Secrets, result.Types, false)
+	},
+}
+
+var importEnvCmd = &cobra.Command{
+	Use:   "env <file>",
+	Short: "Import secrets from a .env file",
+	Long: `Import .env File
+
+Import KEY=value pairs from a dotenv file into a project.
+
+EXAMPLES:
+  uzp import env -p myapp .env
+  uzp import env -p myapp .env --dry-run
+  uzp import env -p myapp .env --lowercase --yes
+  cat .env | uzp import env -p myapp -
+
+SYNTAX:
+  Comments (#), 'export ' prefixes, single, double and backtick quotes,
+  escapes in double quotes (\n \t \" \\) and multiline quoted values.
+
+OUTPUT:
+  + KEY  new secret
+  ~ KEY  changed value
+  = KEY  unchanged
+
+OPTIONS:
+  -p, --project    Project to import into
+  --dry-run        Show the changes without writing
+  -y, --yes        Apply without confirmation
+  --lowercase      Store keys in lowercase (API_KEY -> api_key)`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		if importProject == "" {
+			return fmt.Errorf("missing project name\n\nusage: uzp import env -p PROJECT_NAME FILE")
+		}
+		if err := validateProjectName(importProject); err != nil {
+			return err
+		}
+
+		input := os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", args[0], err)
+			}
+			defer f.Close()
+			input = f
+		}
+
+		entries, err := dotenv.Parse(input)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", args[0], err)
+		}
+
+		incoming := make(map[string]string, len(entries))
+		for _, entry := range entries {
+			// The vault does not store empty values
+			if entry.Value == "" {
+				fmt.Fprintf(os.Stderr, "Skipping %s (line %d): empty value\n", entry.Key, entry.Line)
+				continue
+			}
+
+			key := entry.Key
+			if importLowercase {
+				key = strings.ToLower(key)
+			}
+			incoming[key] = entry.Value
+		}
+
+		if len(incoming) == 0 {
+			fmt.Println("No secrets found in file.")
+			return nil
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		return applyImport(map[string]map[string]string{importProject: incoming}, nil, args[0] == "-")
+	},
+}
+
+func init() {
+	importCmd.PersistentFlags().StringVarP(&importProject, "project", "p", "", "Project to import into")
+	importCmd.PersistentFlags().BoolVar(&importDryRun, "dry-run", false, "Show the changes without writing")
+	importCmd.PersistentFlags().BoolVarP(&importYes, "yes", "y", false, "Apply without confirmation")
+	importCmd.Flags().StringVar(&importFrom, "from", "", "Export format: "+strings.Join(importer.Formats, ", "))
+	importEnvCmd.Flags().BoolVar(&importLowercase, "lowercase", false, "Store keys in lowercase")
+
+	importCmd.AddCommand(importEnvCmd)
+}
+
+// applyImport shows the diff, asks for confirmation unless --yes is set
+// and writes all projects in a single vault transaction. types sets the
+// secret type of keys that are not plain. fromStdin means stdin holds the
+// input and cannot answer a prompt.
+func applyImport(secrets, types map[string]map[string]string, fromStdin bool) error {
+	changes := printImportDiff(secrets, types)
+	if changes == 0 {
+		fmt.Println("Nothing to import.")
+		return nil
+	}
+
+	if importDryRun {
+		fmt.Println("Dry run, no changes written.")
+		return nil
+	}
+
+	if !importYes {
+		if fromStdin {
+			return fmt.Errorf("reading from stdin; pass --yes to apply or --dry-run to preview")
+		}
+
+		ok, err := confirm(bufio.NewReader(os.Stdin), fmt.Sprintf("Import %d secrets into %d project(s)? (y/N): ", changes, len(secrets)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	if err := vault.Import(secrets, types); err != nil {
+		return fmt.Errorf("failed to import secrets: %w", err)
+	}
+
+	fmt.Printf("Imported %d secrets into %d project(s).\n", changes, len(secrets))
+	return nil
+}
+
+// printImportDiff compares incoming secrets with the vault and prints a
+// summary. Values are never shown. It returns the number of new or changed keys.
+func printImportDiff(secrets, types map[string]map[string]string) int {
+	projects := make([]string, 0, len(secrets))
+	for project := range secrets {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	added, changed, unchanged := 0, 0, 0
+	for _, project := range projects {
+		existing, err := vault.GetProjectSecrets(project)
+		if err != nil {
+			existing = map[string]string{}
+		}
+
+		incoming := secrets[project]
+		keys := make([]string, 0, len(incoming))
+		for key := range incoming {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			current, exists := existing[key]
+			switch {
+			case !exists:
+				fmt.Printf("+ %s/%s\n", project, key)
+				added++
+			case current != incoming[key]:
+				fmt.Printf("~ %s/%s\n", project, key)
+				changed++
+			case typeChanged(project, key, types):
+				fmt.Printf("~ %s/%s (type %s)\n", project, key, types[project][key])
+				changed++
+			default:
+				fmt.Printf("= %s/%s\n", project, key)
+				unchanged++
+			}
+		}
+	}
+
+	fmt.Printf("\n%d new, %d changed, %d unchanged\n", added, changed, unchanged)
+	return added + changed
+}
+
+// typeChanged reports whether importing would change the type of an
+// existing secret, e.g. a plain secret re-imported as totp
+func typeChanged(project, key string, types map[string]map[string]string) bool {
+	secretType, typed := types[project][key]
+	if !typed {
+		return false
+	}
+
+	secret, err := vault.GetSecret(project, key)
+	return err == nil && secret.Type != secretType
+}
@@ -2,10 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"syscall"
 
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
 )
 
 var initCmd = &cobra.Command{
@@ -24,37 +23,18 @@ WORKFLOW:
   3. Vault is created and ready to use
 
 STORAGE:
-  Creates ~/.uzp/uzp.vault (encrypted)`,
+  Creates ~/.uzp/uzp.vault (encrypted)
+  Use --vault PATH or UZP_VAULT to create it elsewhere.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if vault already exists
 		if vault.Exists() {
-			return fmt.Errorf("vault already exists")
+			return fmt.Errorf("vault %w at %s", storage.ErrExists, vault.Path())
 		}
 
 		// Prompt for master password
-		fmt.Print("Enter master password: ")
-		password, err := term.ReadPassword(int(syscall.Stdin))
+		password, err := promptNewPassword("master password")
 		if err != nil {
-			return fmt.Errorf("failed to read password: %w", err)
-		}
-		fmt.Println() // New line after password
-
-		// Confirm password
-		fmt.Print("Confirm master password: ")
-		confirmPassword, err := term.ReadPassword(int(syscall.Stdin))
-		if err != nil {
-			return fmt.Errorf("failed to read password: %w", err)
-		}
-		fmt.Println() // New line after password
-
-		// Check if passwords match
-		if string(password) != string(confirmPassword) {
-			return fmt.Errorf("passwords do not match")
-		}
-
-		// Check password strength
-		if len(password) < 8 {
-			return fmt.Errorf("password must be at least 8 characters long")
+			return err
 		}
 
 		// Initialize vault
@@ -62,16 +42,13 @@ STORAGE:
 			return fmt.Errorf("failed to initialize vault: %w", err)
 		}
 
-		fmt.Println("Vault initialized successfully!")
+		fmt.Printf("Vault initialized successfully at %s\n", vault.Path())
 		fmt.Println("Vault is ready to use.")
 
 		// Clear password from memory
 		for i := range password {
 			password[i] = 0
 		}
-		for i := range confirmPassword {
-			confirmPassword[i] = 0
-		}
 
 		return nil
 	},
@@ -3,12 +3,15 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-var projectName string
+var (
+	projectName  string
+	injectFormat string
+)
 
 var injectCmd = &cobra.Command{
 	Use:   "inject",
@@ -35,6 +38,16 @@ OUTPUT FORMAT:
   API_KEY=your_secret_value
   DATABASE_URL=your_connection_string
 
+FORMATS (--format):
+  dotenv      KEY=value, quoted when needed (default)
+  shell       export KEY='value'          eval "$(uzp inject -p myapp -f shell)"
+  fish        set -gx KEY 'value'         uzp inject -p myapp -f fish | source
+  powershell  $env:KEY = 'value'          uzp inject -p myapp -f powershell | iex
+  json        {"KEY": "value"}
+  yaml        "KEY": "value"
+  docker      KEY=value                   docker run --env-file
+  systemd     KEY="value"                 EnvironmentFile=
+
 NOTE:
   Keys are converted to UPPERCASE with underscores`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -43,6 +56,10 @@ NOTE:
 			return fmt.Errorf("missing project name\n\nusage: uzp inject -p PROJECT_NAME > .env\n\nSee 'uzp inject --help' for examples")
 		}
 
+		if !isEnvFormat(injectFormat) {
+			return fmt.Errorf("unsupported format: %s (use one of: %s)", injectFormat, strings.Join(envFormats, ", "))
+		}
+
 		// Check if vault is unlocked, prompt for password if needed
 		if err := ensureVaultUnlocked(); err != nil {
 			return err
@@ -51,27 +68,17 @@ NOTE:
 		// Get project secrets
 		secrets, err := vault.GetProjectSecrets(projectName)
 		if err != nil {
-			return fmt.Errorf("project not found: %s", projectName)
+			return err
 		}
 
-		// Sort keys for consistent output
-		keys := make([]string, 0, len(secrets))
-		for key := range secrets {
-			keys = append(keys, key)
-		}
-		sort.Strings(keys)
+		warnProjectDue(projectName, secrets)
 
 		// Show success feedback to stderr (won't be redirected to file)
 		fmt.Fprintf(os.Stderr, "Exporting %d secrets from project '%s'\n", len(secrets), projectName)
 
-		// Output in .env format
-		fmt.Printf("# Environment variables for project: %s\n", projectName)
-		fmt.Printf("# Generated by uzp\n\n")
-
-		for _, key := range keys {
-			// Convert key to uppercase and replace non-alphanumeric chars with underscore
-			envKey := convertToEnvKey(key)
-			fmt.Printf("%s=%s\n", envKey, secrets[key])
+		// Keys are converted to environment variable names and sorted
+		if err := writeEnv(os.Stdout, injectFormat, projectName, projectEnv(secrets)); err != nil {
+			return err
 		}
 
 		// Success message to stderr
@@ -83,6 +90,7 @@ NOTE:
 
 func init() {
 	injectCmd.Flags().StringVarP(&projectName, "project", "p", "", "Project name to export secrets from")
+	injectCmd.Flags().StringVarP(&injectFormat, "format", "f", formatDotenv, "Output format: "+strings.Join(envFormats, ", "))
 }
 
 // convertToEnvKey converts a key to environment variable format
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hungnguyen18/uzp-cli/internal/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	kdfBenchAlgorithm string
+	kdfBenchTarget    time.Duration
+	kdfAlgorithm      string
+	kdfTarget         time.Duration
+	kdfTime           uint32
+	kdfMemory         uint32
+	kdfThreads        uint8
+)
+
+var kdfCmd = &cobra.Command{
+	Use:   "kdf",
+	Short: "Inspect or upgrade the key derivation function",
+	Long: `Key Derivation
+
+The vault key is derived from the master password with a deliberately slow
+key derivation function (KDF). Its algorithm and cost parameters are stored
+in the vault header, so they can be raised over time without losing data.
+
+EXAMPLES:
+  uzp kdf info                        Show the current KDF parameters
+  uzp kdf benchmark --target 1s       Calibrate parameters for this machine
+  uzp kdf upgrade                     Re-derive with the default argon2id
+  uzp kdf upgrade --target 1s         Re-derive with calibrated parameters
+
+ALGORITHMS:
+  argon2id  Default for new vaults (time=3, memory=64 MiB, threads=4)
+  scrypt    Used by vaults created before argon2id (N=32768, r=8, p=1)`,
+}
+
+var kdfInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the KDF parameters of the vault",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		params, err := vault.KDF()
+		if err != nil {
+			return err
+		}
+
+		elapsed, err := crypto.BenchmarkKDF(params)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("KDF: %s\n", params)
+		fmt.Printf("Unlock time on this machine: %s\n", elapsed.Round(time.Millisecond))
+
+		return nil
+	},
+}
+
+var kdfBenchmarkCmd = &cobra.Command{
+	Use:   "benchmark",
+	Short: "Calibrate KDF parameters to a target unlock time",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Printf("Calibrating %s for a target of %s...\n", kdfBenchAlgorithm, kdfBenchTarget)
+
+		params, elapsed, err := crypto.CalibrateKDF(kdfBenchAlgorithm, kdfBenchTarget)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("KDF: %s\n", params)
+		fmt.Printf("Unlock time on this machine: %s\n", elapsed.Round(time.Millisecond))
+		fmt.Printf("\nApply with: uzp kdf upgrade --algorithm %s --target %s\n", kdfBenchAlgorithm, kdfBenchTarget)
+
+		return nil
+	},
+}
+
+var kdfUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Re-derive the vault key with stronger parameters",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate parameters FIRST before prompting for password
+		params, err := kdfParamsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		// The password is needed again to derive the new key
+		password, err := unlockVault()
+		if err != nil {
+			return err
+		}
+		defer clearBytes(password)
+
+		current, err := vault.KDF()
+		if err != nil {
+			return err
+		}
+
+		if kdfTarget > 0 {
+			fmt.Printf("Calibrating %s for a target of %s...\n", kdfAlgorithm, kdfTarget)
+			params, _, err = crypto.CalibrateKDF(kdfAlgorithm, kdfTarget)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := vault.UpgradeKDF(string(password), params); err != nil {
+			return fmt.Errorf("failed to upgrade KDF: %w", err)
+		}
+
+		refreshAgentKey()
+
+		fmt.Printf("Previous KDF: %s\n", current)
+		fmt.Printf("New KDF:      %s\n", params)
+
+		return nil
+	},
+}
+
+func init() {
+	kdfBenchmarkCmd.Flags().StringVarP(&kdfBenchAlgorithm, "algorithm", "a", crypto.KDFArgon2id, "KDF to calibrate (argon2id or scrypt)")
+	kdfBenchmarkCmd.Flags().DurationVar(&kdfBenchTarget, "target", time.Second, "Target unlock time")
+
+	kdfUpgradeCmd.Flags().StringVarP(&kdfAlgorithm, "algorithm", "a", crypto.KDFArgon2id, "KDF to use (argon2id or scrypt)")
+	kdfUpgradeCmd.Flags().DurationVar(&kdfTarget, "target", 0, "Calibrate parameters to this unlock time")
+	kdfUpgradeCmd.Flags().Uint32Var(&kdfTime, "time", 0, "argon2id passes")
+	kdfUpgradeCmd.Flags().Uint32Var(&kdfMemory, "memory", 0, "argon2id memory in MiB")
+	kdfUpgradeCmd.Flags().Uint8Var(&kdfThreads, "threads", 0, "argon2id parallelism")
+
+	kdfCmd.AddCommand(kdfInfoCmd)
+	kdfCmd.AddCommand(kdfBenchmarkCmd)
+	kdfCmd.AddCommand(kdfUpgradeCmd)
+}
+
+// kdfParamsFromFlags builds the target KDF parameters for kdf upgrade
+func kdfParamsFromFlags(cmd *cobra.Command) (crypto.KDFParams, error) {
+	var params crypto.KDFParams
+
+	switch kdfAlgorithm {
+	case crypto.KDFArgon2id:
+		params = crypto.DefaultKDFParams()
+		if kdfTime > 0 {
+			params.Time = kdfTime
+		}
+		if kdfMemory > 0 {
+			params.Memory = kdfMemory * 1024
+		}
+		if kdfThreads > 0 {
+			params.Threads = kdfThreads
+		}
+	case crypto.KDFScrypt:
+		if cmd.Flags().Changed("time") || cmd.Flags().Changed("memory") || cmd.Flags().Changed("threads") {
+			return params, fmt.Errorf("--time, --memory and --threads only apply to argon2id")
+		}
+		// Twice the legacy cost unless calibrated
+		params = crypto.LegacyKDFParams()
+		params.N *= 2
+	default:
+		return params, fmt.Errorf("unsupported algorithm: %s (use argon2id or scrypt)", kdfAlgorithm)
+	}
+
+	if kdfTarget > 0 && (cmd.Flags().Changed("time") || cmd.Flags().Changed("memory") || cmd.Flags().Changed("threads")) {
+		return params, fmt.Errorf("--target cannot be combined with explicit parameters")
+	}
+
+	return params, params.Validate()
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"sort"
 
 	"github.com/spf13/cobra"
@@ -16,6 +17,8 @@ Display all projects and their keys in the vault.
 
 EXAMPLES:
   uzp list
+  uzp list -o json
+  uzp list -o plain | cut -f1 | sort -u
 
 OUTPUT FORMAT:
   project1:
@@ -23,7 +26,11 @@ OUTPUT FORMAT:
     key2
   
   project2:
-    key3`,
+    key3
+
+  With --output json|yaml|table|plain, one record per secret with the
+  fields project and key, plus type, created_at, updated_at, url, tags,
+  notes and the rotation fields when set (see uzp show).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if vault is unlocked, prompt for password if needed
 		if err := ensureVaultUnlocked(); err != nil {
@@ -36,6 +43,10 @@ OUTPUT FORMAT:
 			return err
 		}
 
+		if outputFormat != "" {
+			return writeRecords(os.Stdout, outputFormat, listColumns, secretRecords(projects))
+		}
+
 		// Check if vault is empty
 		if len(projects) == 0 {
 			fmt.Println("No secrets found.")
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var lockAll bool
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Make the agent forget the vault key",
+	Long: `Lock Vault
+
+Wipe the vault key from the background agent. The next command will
+prompt for the master password again.
+
+EXAMPLES:
+  uzp lock          Lock the current vault
+  uzp lock --all    Lock every vault held by the agent`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := requireAgent()
+		if err != nil {
+			return err
+		}
+
+		if lockAll {
+			if err := client.RemoveAll(); err != nil {
+				return err
+			}
+			fmt.Println("All vaults locked.")
+			return nil
+		}
+
+		if err := client.Remove(vault.Path()); err != nil {
+			return err
+		}
+
+		fmt.Printf("Locked %s.\n", vault.Path())
+		return nil
+	},
+}
+
+func init() {
+	lockCmd.Flags().BoolVarP(&lockAll, "all", "a", false, "Lock every vault held by the agent")
+}
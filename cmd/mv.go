@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	mvProjectMode bool
+	mvForce       bool
+)
+
+var mvCmd = &cobra.Command{
+	Use:   "mv <project/key> <project/key>",
+	Short: "Rename or move a secret or project",
+	Long: `Move Secret
+
+Rename a secret, move it into another project, or rename a whole project.
+The change is written to the vault in a single step.
+
+FORMAT:
+  project/key project/key
+  -p old_project new_project
+
+EXAMPLES:
+  uzp mv backend/api_key api/api_key    Move secret to another project
+  uzp mv myapp/token myapp/api_token    Rename secret
+  uzp mv -p backend api                 Rename project
+
+OPTIONS:
+  -p, --project  Move a whole project instead of a single secret
+  -f, --force    Overwrite existing secrets at the destination`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTransfer(args, mvProjectMode, mvForce, true)
+	},
+}
+
+func init() {
+	mvCmd.Flags().BoolVarP(&mvProjectMode, "project", "p", false, "Move a whole project")
+	mvCmd.Flags().BoolVarP(&mvForce, "force", "f", false, "Overwrite existing secrets at the destination")
+}
+
+// runTransfer moves or copies a secret (or a whole project) for mv and cp
+func runTransfer(args []string, projectMode, force, move bool) error {
+	verb := "Copied"
+	if move {
+		verb = "Moved"
+	}
+
+	if projectMode {
+		// Validate arguments FIRST before prompting for password
+		src, dst := args[0], args[1]
+		for _, name := range []string{src, dst} {
+			if err := validateProjectName(name); err != nil {
+				return err
+			}
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		var err error
+		if move {
+			err = vault.MoveProject(src, dst, force)
+		} else {
+			err = vault.CopyProject(src, dst, force)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s project: %s -> %s\n", verb, src, dst)
+		return nil
+	}
+
+	// Validate arguments FIRST before prompting for password
+	srcProject, srcKey, err := parseSecretPath(args[0])
+	if err != nil {
+		return err
+	}
+	dstProject, dstKey, err := parseSecretPath(args[1])
+	if err != nil {
+		return err
+	}
+
+	// Check if vault is unlocked, prompt for password if needed
+	if err := ensureVaultUnlocked(); err != nil {
+		return err
+	}
+
+	if move {
+		err = vault.Move(srcProject, srcKey, dstProject, dstKey, force)
+	} else {
+		err = vault.Copy(srcProject, srcKey, dstProject, dstKey, force)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: %s/%s -> %s/%s\n", verb, srcProject, srcKey, dstProject, dstKey)
+	return nil
+}
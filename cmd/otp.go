@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/hungnguyen18/uzp-cli/internal/totp"
+	"github.com/hungnguyen18/uzp-cli/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	otpCopy bool
+	otpTTL  int
+)
+
+var otpCmd = &cobra.Command{
+	Use:   "otp <project/key>",
+	Short: "Show the current TOTP code of a secret",
+	Long: `One-Time Password
+
+Print the current RFC 6238 code of a TOTP secret and how many seconds it
+stays valid, or copy it to the clipboard.
+
+TOTP SECRETS:
+  Store an otpauth:// URI (from the QR code) or a base32 seed with
+  --type totp on add, set or update:
+
+    uzp set github/2fa --type totp --value-stdin < seed.txt
+    uzp update github/2fa --type totp        Convert an existing seed
+
+  Bare seeds use SHA1, 6 digits and a 30 second period. For other
+  parameters store the URI, e.g.
+    otpauth://totp/Example:me?secret=JBSWY3DPEHPK3PXP&algorithm=SHA256&digits=8&period=60
+  SHA1, SHA256 and SHA512, 6 to 8 digits and any period are supported.
+
+EXAMPLES:
+  uzp otp github/2fa
+  uzp otp github/2fa --copy
+  uzp otp github/2fa -o plain              Only the code, for scripts
+
+OUTPUT:
+  By default the code is printed with the seconds remaining. With -o plain
+  only the code is printed; with -o json|yaml|table a record with the
+  fields project, key, code and remaining_seconds.
+
+OPTIONS:
+  --copy  Copy the code to the clipboard instead of printing it
+  --ttl   Seconds before clipboard is cleared (default: 15)`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		project, key, err := parseSecretPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		secret, err := vault.GetSecret(project, key)
+		if err != nil {
+			return err
+		}
+		if secret.Type != storage.TypeTOTP {
+			return fmt.Errorf("secret '%s/%s' is not a TOTP secret (convert it with: uzp update %s/%s --type totp)", project, key, project, key)
+		}
+		warnIfDue(project, key, secret)
+
+		otpKey, err := totp.Parse(secret.Value)
+		if err != nil {
+			return fmt.Errorf("secret '%s/%s': %w", project, key, err)
+		}
+		defer otpKey.Clear()
+
+		now := time.Now()
+		code, err := otpKey.Code(now)
+		if err != nil {
+			return err
+		}
+		remaining := int(otpKey.Remaining(now).Seconds())
+
+		if otpCopy {
+			if err := utils.CopyToClipboard(code, time.Duration(otpTTL)*time.Second); err != nil {
+				return err
+			}
+			fmt.Printf("Copied code for %s/%s to clipboard (valid for %ds).\n", project, key, remaining)
+			fmt.Printf("Clipboard will be cleared in %d seconds.\n", otpTTL)
+			return nil
+		}
+
+		switch outputFormat {
+		case "":
+			fmt.Printf("%s (%ds remaining)\n", code, remaining)
+			return nil
+		case outputPlain:
+			fmt.Println(code)
+			return nil
+		default:
+			record := outputRecord{
+				{Name: "project", Value: project},
+				{Name: "key", Value: key},
+				{Name: "code", Value: code},
+				{Name: "remaining_seconds", Value: remaining},
+			}
+			return writeRecords(os.Stdout, outputFormat, []string{"project", "key", "code", "remaining_seconds"}, []outputRecord{record})
+		}
+	},
+}
+
+func init() {
+	otpCmd.Flags().BoolVar(&otpCopy, "copy", false, "Copy the code to the clipboard instead of printing it")
+	otpCmd.Flags().IntVarP(&otpTTL, "ttl", "t", 15, "Time to live in seconds before clipboard is cleared")
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats for read commands (--output)
+const (
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTable = "table"
+	outputPlain = "plain"
+)
+
+var outputFormats = []string{outputJSON, outputYAML, outputTable, outputPlain}
+
+// outputField is a named value in a record. Values are strings, string
+// slices or numbers; empty values are optional and left out of JSON and YAML.
+type outputField struct {
+	Name  string
+	Value interface{}
+}
+
+// outputRecord is one row of output; fields keep their order so the
+// schema is stable
+type outputRecord []outputField
+
+// isOutputFormat reports whether format is a supported --output value
+func isOutputFormat(format string) bool {
+	for _, f := range outputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// validateOutputFormat checks the global --output flag
+func validateOutputFormat() error {
+	if outputFormat != "" && !isOutputFormat(outputFormat) {
+		return fmt.Errorf("unsupported output format: %s (use one of: %s)", outputFormat, strings.Join(outputFormats, ", "))
+	}
+	return nil
+}
+
+// writeRecords writes records in a machine-readable format:
+//
+//	json   array of objects
+//	yaml   sequence of mappings
+//	table  aligned columns with a header
+//	plain  tab-separated values, one record per line, no header
+func writeRecords(w io.Writer, format string, columns []string, records []outputRecord) error {
+	var buf bytes.Buffer
+
+	switch format {
+	case outputJSON:
+		buf.WriteString("[")
+		for i, record := range records {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n  {")
+			first := true
+			for _, field := range record {
+				if isEmptyValue(field.Value) {
+					continue
+				}
+				if !first {
+					buf.WriteString(",")
+				}
+				fmt.Fprintf(&buf, "\n    %s: %s", quoteJSON(field.Name), encodeJSON(field.Value))
+				first = false
+			}
+			buf.WriteString("\n  }")
+		}
+		if len(records) > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("]\n")
+
+	case outputYAML:
+		if len(records) == 0 {
+			buf.WriteString("[]\n")
+		}
+		for _, record := range records {
+			prefix := "- "
+			for _, field := range record {
+				if isEmptyValue(field.Value) {
+					continue
+				}
+				// JSON scalars and arrays are valid YAML flow values
+				fmt.Fprintf(&buf, "%s%s: %s\n", prefix, field.Name, encodeJSON(field.Value))
+				prefix = "  "
+			}
+		}
+
+	case outputTable:
+		tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+		for _, record := range records {
+			fmt.Fprintln(tw, strings.Join(recordValues(record, columns), "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+	case outputPlain:
+		for _, record := range records {
+			fmt.Fprintln(&buf, strings.Join(recordValues(record, columns), "\t"))
+		}
+
+	default:
+		return fmt.Errorf("unsupported output format: %s (use one of: %s)", format, strings.Join(outputFormats, ", "))
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// recordValues returns the record's values for columns as single-line text
+func recordValues(record outputRecord, columns []string) []string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		for _, field := range record {
+			if field.Name != column {
+				continue
+			}
+			var text string
+			switch v := field.Value.(type) {
+			case []string:
+				text = strings.Join(v, ",")
+			default:
+				text = fmt.Sprint(v)
+			}
+			// Keep one record per line
+			values[i] = strings.NewReplacer("\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(text)
+		}
+	}
+	return values
+}
+
+// isEmptyValue reports whether an optional field has no value
+func isEmptyValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
+
+// encodeJSON encodes v as compact JSON without HTML escaping
+func encodeJSON(v interface{}) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// listColumns are the table and plain columns of list and search
+var listColumns = []string{"project", "key", "updated_at", "tags"}
+
+// secretRecords converts a project -> keys map into sorted records with
+// each secret's metadata (never its value)
+func secretRecords(projects map[string][]string) []outputRecord {
+	names := make([]string, 0, len(projects))
+	for project := range projects {
+		names = append(names, project)
+	}
+	sort.Strings(names)
+
+	var records []outputRecord
+	for _, project := range names {
+		keys := append([]string(nil), projects[project]...)
+		sort.Strings(keys)
+		for _, key := range keys {
+			secret, err := vault.GetSecret(project, key)
+			if err != nil {
+				continue
+			}
+			records = append(records, secretRecord(project, key, secret))
+		}
+	}
+	return records
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var passwdCmd = &cobra.Command{
+	Use:   "passwd",
+	Short: "Change the master password",
+	Long: `Change Master Password
+
+Re-encrypt the vault under a new master password. All secrets are kept.
+
+EXAMPLES:
+  uzp passwd
+  uzp --profile work passwd
+
+WORKFLOW:
+  1. Enter current master password
+  2. Enter new master password (minimum 8 characters)
+  3. Confirm new password
+
+NOTE:
+  A new salt is generated and the previous backup file is removed,
+  so the old password can no longer open any copy of the vault.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Always require the current password, even if the agent holds the key
+		currentPassword, err := unlockVault()
+		if err != nil {
+			return err
+		}
+		clearBytes(currentPassword)
+
+		password, err := promptNewPassword("new master password")
+		if err != nil {
+			return err
+		}
+		defer clearBytes(password)
+
+		if err := vault.ChangePassword(string(password)); err != nil {
+			return fmt.Errorf("failed to change master password: %w", err)
+		}
+
+		refreshAgentKey()
+
+		fmt.Println("Master password changed successfully.")
+
+		return nil
+	},
+}
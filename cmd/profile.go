@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profileCreateUse bool
+	profileDeleteYes bool
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named vault profiles",
+	Long: `Vault Profiles
+
+Keep separate vaults (each with its own master password) for work,
+personal use or individual clients, and switch between them by name.
+
+EXAMPLES:
+  uzp profile list                 Show all profiles
+  uzp profile create work          Create a new profile vault
+  uzp profile use work             Make 'work' the default profile
+  uzp profile delete client-acme   Delete a profile and its secrets
+  uzp --profile personal list      Use a profile for one command
+
+SELECTION:
+  --profile NAME or UZP_PROFILE overrides the default for one command.
+  The 'default' profile is the original ~/.uzp/uzp.vault.`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles, err := storage.ListProfiles()
+		if err != nil {
+			return err
+		}
+
+		if len(profiles) == 0 {
+			fmt.Println("No profiles found. Run 'uzp init' or 'uzp profile create NAME'.")
+			return nil
+		}
+
+		current, err := storage.CurrentProfile()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range profiles {
+			if name == current {
+				fmt.Printf("* %s\n", name)
+			} else {
+				fmt.Printf("  %s\n", name)
+			}
+		}
+
+		return nil
+	},
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a new profile with its own master password",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		path, err := storage.ProfileVaultPath(name)
+		if err != nil {
+			return err
+		}
+
+		profileVault := storage.NewVaultAt(path)
+		if profileVault.Exists() {
+			return fmt.Errorf("profile %w: %s", storage.ErrExists, name)
+		}
+
+		password, err := promptNewPassword(fmt.Sprintf("master password for '%s'", name))
+		if err != nil {
+			return err
+		}
+		defer clearBytes(password)
+
+		if err := profileVault.Initialize(string(password)); err != nil {
+			return fmt.Errorf("failed to create profile: %w", err)
+		}
+		profileVault.Lock()
+
+		fmt.Printf("Created profile: %s\n", name)
+
+		if profileCreateUse {
+			if err := storage.SetCurrentProfile(name); err != nil {
+				return err
+			}
+			fmt.Printf("Default profile set to: %s\n", name)
+		}
+
+		return nil
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the default profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		exists, err := storage.ProfileExists(name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("profile %w: %s", storage.ErrNotFound, name)
+		}
+
+		if err := storage.SetCurrentProfile(name); err != nil {
+			return err
+		}
+
+		fmt.Printf("Default profile set to: %s\n", name)
+		return nil
+	},
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a profile and all of its secrets",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		exists, err := storage.ProfileExists(name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("profile %w: %s", storage.ErrNotFound, name)
+		}
+
+		if !profileDeleteYes {
+			fmt.Printf("WARNING: This will permanently delete profile '%s' and ALL of its secrets!\n", name)
+			ok, err := confirm(bufio.NewReader(os.Stdin), "Delete? (y/N): ")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+		}
+
+		if err := storage.DeleteProfile(name); err != nil {
+			return fmt.Errorf("failed to delete profile: %w", err)
+		}
+
+		fmt.Printf("Deleted profile: %s\n", name)
+		return nil
+	},
+}
+
+func init() {
+	profileCreateCmd.Flags().BoolVar(&profileCreateUse, "use", false, "Also make the new profile the default")
+	profileDeleteCmd.Flags().BoolVarP(&profileDeleteYes, "yes", "y", false, "Skip confirmation prompt")
+
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileDeleteCmd)
+}
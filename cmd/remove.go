@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	removeProject string
+	removeYes     bool
+)
+
+var removeCmd = &cobra.Command{
+	Use:     "remove [project/key]",
+	Aliases: []string{"rm"},
+	Short:   "Remove a secret or an entire project",
+	Long: `Remove Secret
+
+Delete a single secret, or every secret in a project.
+
+FORMAT:
+  project/key
+
+EXAMPLES:
+  uzp remove myapp/api_key        Remove one secret
+  uzp remove -p myapp             Remove the whole project
+  uzp remove myapp/api_key --yes  Skip confirmation
+
+OPTIONS:
+  -p, --project  Remove every secret in the project
+  -y, --yes      Do not ask for confirmation
+
+NOTE:
+  Projects left without any secrets are removed automatically.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		if removeProject == "" && len(args) == 0 {
+			return fmt.Errorf("usage: uzp remove <project/key> or uzp remove -p PROJECT_NAME")
+		}
+		if removeProject != "" && len(args) > 0 {
+			return fmt.Errorf("specify either project/key or --project, not both")
+		}
+
+		var project, key string
+		if len(args) > 0 {
+			var err error
+			project, key, err = parseSecretPath(args[0])
+			if err != nil {
+				return err
+			}
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		reader := bufio.NewReader(os.Stdin)
+
+		if removeProject != "" {
+			secrets, err := vault.GetProjectSecrets(removeProject)
+			if err != nil {
+				return err
+			}
+
+			if !removeYes {
+				fmt.Printf("This will permanently delete %d secrets in project '%s'.\n", len(secrets), removeProject)
+				ok, err := confirm(reader, "Remove? (y/N): ")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+			}
+
+			if err := vault.RemoveProject(removeProject); err != nil {
+				return fmt.Errorf("failed to remove project: %w", err)
+			}
+
+			fmt.Printf("Removed project: %s\n", removeProject)
+			return nil
+		}
+
+		// Check if secret exists
+		if _, err := vault.Get(project, key); err != nil {
+			return err
+		}
+
+		if !removeYes {
+			ok, err := confirm(reader, fmt.Sprintf("Remove '%s/%s'? (y/N): ", project, key))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+		}
+
+		if err := vault.Remove(project, key); err != nil {
+			return fmt.Errorf("failed to remove secret: %w", err)
+		}
+
+		fmt.Printf("Removed: %s/%s\n", project, key)
+
+		return nil
+	},
+}
+
+func init() {
+	removeCmd.Flags().StringVarP(&removeProject, "project", "p", "", "Remove every secret in the project")
+	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "Skip confirmation prompt")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rollbackTo  int
+	rollbackYes bool
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <project/key> --to N",
+	Short: "Restore a previous value of a secret",
+	Long: `Rollback Secret
+
+Restore revision N from 'uzp history' as the current value. The value
+being replaced is added to the history, so a rollback can be undone
+with 'uzp rollback --to 1'.
+
+EXAMPLES:
+  uzp history myapp/api_key
+  uzp rollback myapp/api_key --to 1      Restore the previous value
+  uzp rollback myapp/api_key --to 3 -y
+
+OPTIONS:
+  --to N      Revision to restore (1 is the most recent previous value)
+  -y, --yes   Skip confirmation`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		project, key, err := parseSecretPath(args[0])
+		if err != nil {
+			return err
+		}
+		if rollbackTo < 1 {
+			return fmt.Errorf("missing revision\n\nusage: uzp rollback project/key --to N (see uzp history)")
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		secret, err := vault.GetSecret(project, key)
+		if err != nil {
+			return err
+		}
+		if rollbackTo > len(secret.History) {
+			return fmt.Errorf("revision %d of %s/%s %w: it has %d previous values (see uzp history)", rollbackTo, project, key, storage.ErrNotFound, len(secret.History))
+		}
+
+		if !rollbackYes {
+			revision := secret.History[rollbackTo-1]
+			prompt := fmt.Sprintf("Restore %s/%s to revision %d (set %s)? (y/N): ", project, key, rollbackTo, formatTime(revision.SetAt))
+			ok, err := confirm(bufio.NewReader(os.Stdin), prompt)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+		}
+
+		if err := vault.Rollback(project, key, rollbackTo); err != nil {
+			return fmt.Errorf("failed to roll back secret: %w", err)
+		}
+
+		fmt.Printf("Rolled back: %s/%s to revision %d\n", project, key, rollbackTo)
+		return nil
+	},
+}
+
+func init() {
+	rollbackCmd.Flags().IntVar(&rollbackTo, "to", 0, "Revision to restore (see uzp history)")
+	rollbackCmd.Flags().BoolVarP(&rollbackYes, "yes", "y", false, "Skip confirmation")
+}
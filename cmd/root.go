@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/hungnguyen18/uzp-cli/internal/storage"
 	"github.com/spf13/cobra"
@@ -13,7 +15,13 @@ var Version = "dev" // Default value, will be overridden by ldflags during build
 var (
 	vault       *storage.Vault
 	showVersion bool
-	rootCmd     = &cobra.Command{
+	vaultPath   string
+	profileName string
+	// Master password sources for automation
+	passwordFD   int
+	passwordFile string
+	outputFormat string
+	rootCmd      = &cobra.Command{
 		Use:   "uzp",
 		Short: "Secure secrets manager",
 		Long: `UZP - Secure Secrets Manager
@@ -30,14 +38,47 @@ BASIC USAGE:
   uzp list                    List all secrets
   uzp get project/key         Get secret value
   uzp update project/key      Update secret
+  uzp remove project/key      Remove secret
   uzp inject -p project       Export as environment variables
+  uzp run -p project -- cmd   Run a command with project secrets
 
 EXAMPLES:
   uzp inject -p myapp > .env  Export secrets to .env file
   uzp copy myapp/api_key      Copy secret to clipboard
+  uzp otp github/2fa          Show a TOTP code
   uzp search database         Search for secrets
+  uzp generate myapp/db_pass  Store a random password
 
-STORAGE: ~/.uzp/uzp.vault (encrypted)`,
+STORAGE: ~/.uzp/uzp.vault (encrypted)
+  Override with --vault PATH or the UZP_VAULT environment variable,
+  or select a named profile with --profile NAME or UZP_PROFILE.
+  If XDG_DATA_HOME is set and ~/.uzp/uzp.vault does not exist,
+  $XDG_DATA_HOME/uzp/uzp.vault is used instead.
+
+AUTOMATION:
+  The master password is read from the first of:
+    --password-fd N       first line of file descriptor N
+    --password-file PATH  first line of PATH (should be mode 0600)
+    UZP_PASSWORD          environment variable (warns; avoid if possible)
+    UZP_ASKPASS           program printing the password on stdout
+  and from the terminal otherwise. New passwords (init, passwd) are
+  always read from the terminal.
+
+EXIT CODES:
+  0  success                      4  not found
+  1  any other error              5  already exists
+  2  wrong master password        6  vault corrupt or header tampered
+  3  vault locked, no password    7  'uzp due --check' found due secrets
+  'uzp run' exits with the status of the command it runs.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(); err != nil {
+				return err
+			}
+			if !needsVault(cmd) {
+				return nil
+			}
+			return openVault()
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if showVersion {
 				fmt.Printf("uzp version %s\n", Version)
@@ -53,22 +94,122 @@ func init() {
 	// Add version flags
 	rootCmd.Flags().BoolVarP(&showVersion, "version", "v", false, "Print version information")
 
-	// Initialize vault instance
-	vault = storage.NewVault()
+	// Vault location flag, shared by every subcommand
+	rootCmd.PersistentFlags().StringVar(&vaultPath, "vault", "", "Path to the vault file (env: UZP_VAULT)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Named vault profile to use (env: UZP_PROFILE)")
+
+	// Machine-readable output for read commands
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "Output format for read commands (list, search, get, show, history, due, otp): "+strings.Join(outputFormats, ", "))
+
+	// Non-interactive master password sources
+	rootCmd.PersistentFlags().IntVar(&passwordFD, "password-fd", -1, "Read the master password from file descriptor N")
+	rootCmd.PersistentFlags().StringVar(&passwordFile, "password-file", "", "Read the master password from a file")
 
 	// Add all subcommands
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(setCmd)
+	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(dueCmd)
 	rootCmd.AddCommand(copyCmd)
+	rootCmd.AddCommand(otpCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(injectCmd)
+	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(mvCmd)
+	rootCmd.AddCommand(cpCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(passwdCmd)
+	rootCmd.AddCommand(kdfCmd)
+	rootCmd.AddCommand(agentCmd)
+	rootCmd.AddCommand(unlockCmd)
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
 	rootCmd.AddCommand(resetCmd)
+
+	// These commands never touch the vault, so a bad profile or config file
+	// must not stop them (the profile commands are how the user fixes it)
+	for _, c := range []*cobra.Command{
+		rootCmd,
+		profileListCmd, profileCreateCmd, profileUseCmd, profileDeleteCmd,
+		kdfBenchmarkCmd,
+		agentCmd, agentStatusCmd, agentStopCmd,
+	} {
+		c.Annotations = map[string]string{noVaultAnnotation: "true"}
+	}
+}
+
+// noVaultAnnotation marks commands that run without opening the vault
+const noVaultAnnotation = "uzp:no-vault"
+
+// needsVault reports whether cmd uses the vault. Cobra's own help and
+// completion commands never do.
+func needsVault(cmd *cobra.Command) bool {
+	if _, ok := cmd.Annotations[noVaultAnnotation]; ok {
+		return false
+	}
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return false
+		}
+	}
+	return true
+}
+
+// openVault initializes the vault instance. An explicit path (--vault,
+// UZP_VAULT) takes precedence over a profile (--profile, UZP_PROFILE),
+// which takes precedence over the default profile from the config file.
+func openVault() error {
+	if vaultPath != "" && profileName != "" {
+		return fmt.Errorf("use either --vault or --profile, not both")
+	}
+
+	path := vaultPath
+	if path == "" && profileName == "" {
+		path = os.Getenv("UZP_VAULT")
+	}
+
+	if path == "" {
+		v, err := storage.NewVault(activeProfile())
+		if err != nil {
+			return err
+		}
+		vault = v
+		return nil
+	}
+
+	vault = storage.NewVaultAt(path)
+	return nil
+}
+
+// activeProfile returns the profile selected by --profile or UZP_PROFILE, if any
+func activeProfile() string {
+	if profileName != "" {
+		return profileName
+	}
+	return os.Getenv("UZP_PROFILE")
 }
 
 // Execute runs the root command
 func Execute() error {
 	return rootCmd.Execute()
 }
+
+// ExitError asks main to exit with a specific status code without printing
+// an error, e.g. to propagate the exit code of a command run by uzp
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
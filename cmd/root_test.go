@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNeedsVault(t *testing.T) {
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd()
+
+	for args, want := range map[string]bool{
+		"":               false,
+		"help":           false,
+		"profile list":   false,
+		"profile use":    false,
+		"kdf benchmark":  false,
+		"agent status":   false,
+		"completion zsh": false,
+		"list":           true,
+		"get":            true,
+		"lock":           true,
+		"kdf upgrade":    true,
+	} {
+		cmd, _, err := rootCmd.Find(strings.Fields(args))
+		if err != nil {
+			t.Fatalf("Find(%q): %v", args, err)
+		}
+		if got := needsVault(cmd); got != want {
+			t.Errorf("needsVault(%q) = %v, want %v", args, got, want)
+		}
+	}
+}
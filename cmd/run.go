@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+var runProject string
+
+var runCmd = &cobra.Command{
+	Use:   "run -p PROJECT -- <command> [args...]",
+	Short: "Run a command with project secrets in its environment",
+	Long: `Run With Secrets
+
+Run a command with a project's secrets added to its environment.
+Secrets are passed in memory only and never written to disk.
+
+USAGE:
+  uzp run --project PROJECT_NAME -- COMMAND [ARGS...]
+
+EXAMPLES:
+  uzp run -p myapp -- npm start
+  uzp run -p backend -- go run ./cmd/server
+  uzp run -p aws -- terraform plan
+
+BEHAVIOUR:
+  - Keys are converted to UPPERCASE with underscores, as in 'uzp inject'
+  - Secrets override variables already set in the environment
+  - stdin, stdout and stderr are passed through
+  - Signals are forwarded to the command; Ctrl-C in a terminal reaches
+    it directly and is not sent a second time
+  - uzp exits with the command's exit code`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		if runProject == "" {
+			return fmt.Errorf("missing project name\n\nusage: uzp run -p PROJECT_NAME -- COMMAND [ARGS...]")
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		secrets, err := vault.GetProjectSecrets(runProject)
+		if err != nil {
+			return err
+		}
+
+		vars := projectEnv(secrets)
+		overrides := make([]string, 0, len(vars))
+		for _, v := range vars {
+			overrides = append(overrides, v.Name+"="+v.Value)
+		}
+		env := mergeEnv(os.Environ(), overrides)
+
+		// The child has what it needs; drop the key and data from memory
+		vault.Lock()
+
+		child := exec.Command(args[0], args[1:]...)
+		child.Env = env
+		child.Stdin = os.Stdin
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+
+		if err := child.Start(); err != nil {
+			return fmt.Errorf("failed to start %s: %w", args[0], err)
+		}
+
+		// Forward signals to the child until it exits. Ctrl-C, Ctrl-\ and a
+		// hangup already reach a child in the terminal's foreground process
+		// group, so forwarding those would deliver them twice.
+		fromTerminal := inForegroundGroup()
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT)
+		forwarded := make(chan struct{})
+		go func() {
+			defer close(forwarded)
+			for sig := range signals {
+				if fromTerminal && sig != syscall.SIGTERM {
+					continue
+				}
+				_ = child.Process.Signal(sig)
+			}
+		}()
+
+		err = child.Wait()
+		signal.Stop(signals)
+		close(signals)
+		<-forwarded
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			return &ExitError{Code: childExitCode(exitErr.ProcessState)}
+		}
+		return err
+	},
+}
+
+func init() {
+	runCmd.Flags().StringVarP(&runProject, "project", "p", "", "Project whose secrets are added to the environment")
+	// Everything after the command name belongs to the command
+	runCmd.Flags().SetInterspersed(false)
+}
+
+// projectEnv converts project secrets to environment variables, sorted by name.
+// Keys that map to the same variable name are reported on stderr, and the
+// last key in sort order wins.
+func projectEnv(secrets map[string]string) []envVar {
+	keys := make([]string, 0, len(secrets))
+	for key := range secrets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	seen := make(map[string]string, len(keys))
+	for _, key := range keys {
+		envKey := convertToEnvKey(key)
+		if previous, ok := seen[envKey]; ok {
+			fmt.Fprintf(os.Stderr, "Warning: '%s' and '%s' both map to %s; using '%s'\n", previous, key, envKey, key)
+		}
+		seen[envKey] = key
+	}
+
+	env := make([]envVar, 0, len(seen))
+	for envKey, key := range seen {
+		env = append(env, envVar{Name: envKey, Value: secrets[key]})
+	}
+	sort.Slice(env, func(i, j int) bool { return env[i].Name < env[j].Name })
+	return env
+}
+
+// mergeEnv returns base with overrides applied, later entries winning
+func mergeEnv(base, overrides []string) []string {
+	index := make(map[string]int, len(base)+len(overrides))
+	result := make([]string, 0, len(base)+len(overrides))
+
+	for _, entry := range append(append([]string{}, base...), overrides...) {
+		name := entry
+		if i := strings.IndexByte(entry, '='); i >= 0 {
+			name = entry[:i]
+		}
+
+		if i, ok := index[name]; ok {
+			result[i] = entry
+			continue
+		}
+		index[name] = len(result)
+		result = append(result, entry)
+	}
+	return result
+}
+
+// childExitCode returns the exit code to propagate, using the shell
+// convention of 128+signal for a child killed by a signal
+func childExitCode(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return state.ExitCode()
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package cmd
+
+// inForegroundGroup reports whether console signals reach the child
+// directly; on Windows every process attached to the console gets Ctrl-C
+func inForegroundGroup() bool {
+	return true
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"sort"
 
 	"github.com/spf13/cobra"
@@ -20,7 +21,8 @@ EXAMPLES:
   uzp search myapp
 
 OUTPUT:
-  Shows matching projects and keys in the same format as list.`,
+  Shows matching projects and keys in the same format as list,
+  including --output json|yaml|table|plain.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if vault is unlocked, prompt for password if needed
@@ -36,6 +38,10 @@ OUTPUT:
 			return err
 		}
 
+		if outputFormat != "" {
+			return writeRecords(os.Stdout, outputFormat, listColumns, secretRecords(results))
+		}
+
 		// Check if no results
 		if len(results) == 0 {
 			fmt.Printf("No results found for '%s'\n", keyword)
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	setValueStdin bool
+	setValueFile  string
+	setFromEnv    string
+	setYes        bool
+	setMeta       secretMeta
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set <project/key>",
+	Short: "Set a secret non-interactively",
+	Long: `Set Secret
+
+Add or overwrite a secret without interactive prompts, for CI jobs and
+provisioning scripts.
+
+EXAMPLES:
+  printf '%s' "$TOKEN" | uzp set myapp/api_token --value-stdin
+  uzp set myapp/tls_key --value-file key.pem --yes
+  uzp set myapp/db_password --from-env DB_PASSWORD --yes
+  uzp set myapp/api_key                  Prompt for the value (terminal only)
+  uzp set github/2fa --type totp --value-file seed.txt
+  printf '%s\n%s' "$MASTER" "$TOKEN" | uzp set ci/token --password-fd 0 --value-stdin -y
+
+OPTIONS:
+  --value-stdin              Read the value from stdin
+  --value-file PATH          Read the value from a file
+  --from-env VAR             Read the value from an environment variable
+  -y, --yes                  Overwrite an existing secret without confirmation
+  --type totp                TOTP seed or otpauth:// URI (see uzp otp)
+  --note, --tag, --url       Metadata stored with the secret
+  --expires, --rotate-every  Expiry and rotation reminders (see uzp due)
+
+NOTE:
+  A single trailing newline is removed from stdin and file values.
+  Without --yes, overwriting an existing secret requires a terminal
+  to confirm; scripts must pass --yes.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		project, key, err := parseSecretPath(args[0])
+		if err != nil {
+			return err
+		}
+		if err := setMeta.validate(); err != nil {
+			return err
+		}
+
+		sources := 0
+		for _, set := range []bool{setValueStdin, setValueFile != "", setFromEnv != ""} {
+			if set {
+				sources++
+			}
+		}
+		if sources > 1 {
+			return fmt.Errorf("use only one of --value-stdin, --value-file and --from-env")
+		}
+		if sources == 0 && !stdinIsTerminal() {
+			return fmt.Errorf("stdin is not a terminal; use --value-stdin, --value-file or --from-env")
+		}
+
+		// With --password-fd 0 the password is the first line of stdin,
+		// so unlock before reading the value from the rest of it
+		if setValueStdin {
+			if err := ensureVaultUnlocked(); err != nil {
+				return err
+			}
+		}
+
+		reader := bufio.NewReader(os.Stdin)
+		valueBytes, err := readSetValue(reader)
+		if err != nil {
+			return err
+		}
+		defer clearBytes(valueBytes)
+
+		if len(valueBytes) == 0 {
+			return fmt.Errorf("value cannot be empty")
+		}
+		value := string(valueBytes)
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		// Check if key already exists
+		currentValue, err := vault.Get(project, key)
+		isUpdate := err == nil
+
+		if isUpdate && currentValue == value && !setMeta.changed(cmd) {
+			fmt.Println("No changes made.")
+			return nil
+		}
+
+		if isUpdate && !setYes {
+			// stdin may hold the value or belong to a script
+			if setValueStdin || !stdinIsTerminal() {
+				return fmt.Errorf("secret '%s/%s' %w; pass --yes to overwrite", project, key, storage.ErrExists)
+			}
+
+			ok, err := confirm(reader, fmt.Sprintf("Secret '%s/%s' already exists. Update? (y/N): ", project, key))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+		}
+
+		err = vault.Put(project, key, func(secret *storage.Secret) error {
+			secret.Value = value
+			return setMeta.apply(cmd, secret)
+		})
+		if err != nil {
+			if isUpdate {
+				return fmt.Errorf("failed to update secret: %w", err)
+			}
+			return fmt.Errorf("failed to add secret: %w", err)
+		}
+
+		if isUpdate {
+			fmt.Printf("Updated: %s/%s\n", project, key)
+		} else {
+			fmt.Printf("Added: %s/%s\n", project, key)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	setCmd.Flags().BoolVar(&setValueStdin, "value-stdin", false, "Read the value from stdin")
+	setCmd.Flags().StringVar(&setValueFile, "value-file", "", "Read the value from a file")
+	setCmd.Flags().StringVar(&setFromEnv, "from-env", "", "Read the value from an environment variable")
+	setCmd.Flags().BoolVarP(&setYes, "yes", "y", false, "Overwrite an existing secret without confirmation")
+	setMeta.addFlags(setCmd)
+}
+
+// readSetValue reads the value from the source selected by the flags,
+// prompting on the terminal when none was given
+func readSetValue(reader *bufio.Reader) ([]byte, error) {
+	switch {
+	case setValueStdin:
+		value, err := io.ReadAll(reader)
+		if err != nil {
+			clearBytes(value)
+			return nil, fmt.Errorf("failed to read value from stdin: %w", err)
+		}
+		return trimNewline(value), nil
+	case setValueFile != "":
+		value, err := os.ReadFile(setValueFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read value file: %w", err)
+		}
+		return trimNewline(value), nil
+	case setFromEnv != "":
+		value, ok := os.LookupEnv(setFromEnv)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", setFromEnv)
+		}
+		return []byte(value), nil
+	default:
+		value, err := readHidden(reader, "Value (hidden): ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read value: %w", err)
+		}
+		return value, nil
+	}
+}
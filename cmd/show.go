@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var showReveal bool
+
+var showCmd = &cobra.Command{
+	Use:   "show <project/key>",
+	Short: "Show a secret's metadata",
+	Long: `Show Secret
+
+Show when a secret was created and last updated, with its notes, tags
+and URL. The value is only shown with --reveal.
+
+EXAMPLES:
+  uzp show myapp/api_key
+  uzp show myapp/api_key --reveal
+  uzp show myapp/api_key -o json
+
+OUTPUT:
+  With --output json|yaml|table|plain, a record with the fields project,
+  key, type, created_at, updated_at, url, tags, notes, expires_at,
+  rotate_every, due_at (and value with --reveal).
+  Empty fields are omitted from JSON and YAML.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Validate arguments FIRST before prompting for password
+		project, key, err := parseSecretPath(args[0])
+		if err != nil {
+			return err
+		}
+
+		// Check if vault is unlocked, prompt for password if needed
+		if err := ensureVaultUnlocked(); err != nil {
+			return err
+		}
+
+		secret, err := vault.GetSecret(project, key)
+		if err != nil {
+			return err
+		}
+
+		record := secretRecord(project, key, secret)
+		columns := []string{"project", "key", "type", "created_at", "updated_at", "url", "tags", "notes", "expires_at", "rotate_every", "due_at"}
+		if showReveal {
+			record = append(record, outputField{Name: "value", Value: secret.Value})
+			columns = append(columns, "value")
+		}
+
+		if outputFormat != "" {
+			return writeRecords(os.Stdout, outputFormat, columns, []outputRecord{record})
+		}
+
+		fmt.Printf("Secret:   %s/%s\n", project, key)
+		if secret.Type != storage.TypePlain {
+			fmt.Printf("Type:     %s\n", secret.Type)
+		}
+		fmt.Printf("Created:  %s\n", formatTime(secret.CreatedAt))
+		fmt.Printf("Updated:  %s\n", formatTime(secret.UpdatedAt))
+		if secret.URL != "" {
+			fmt.Printf("URL:      %s\n", secret.URL)
+		}
+		if len(secret.Tags) > 0 {
+			fmt.Printf("Tags:     %s\n", strings.Join(secret.Tags, ", "))
+		}
+		if secret.Notes != "" {
+			fmt.Printf("Notes:    %s\n", strings.ReplaceAll(secret.Notes, "\n", "\n          "))
+		}
+		if secret.ExpiresAt != nil {
+			fmt.Printf("Expires:  %s\n", formatTime(*secret.ExpiresAt))
+		}
+		if secret.RotateEvery != "" {
+			fmt.Printf("Rotate:   every %s\n", secret.RotateEvery)
+		}
+		if due, ok := secret.DueAt(); ok {
+			fmt.Printf("Due:      %s (%s)\n", formatTime(due), describeDue(due))
+		}
+		if showReveal {
+			fmt.Printf("Value:    %s\n", secret.Value)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	showCmd.Flags().BoolVar(&showReveal, "reveal", false, "Also show the secret value")
+}
+
+// secretRecord returns the metadata of a secret as an output record,
+// without its value
+func secretRecord(project, key string, secret storage.Secret) outputRecord {
+	return outputRecord{
+		{Name: "project", Value: project},
+		{Name: "key", Value: key},
+		{Name: "type", Value: secret.Type},
+		{Name: "created_at", Value: formatTimestamp(secret.CreatedAt)},
+		{Name: "updated_at", Value: formatTimestamp(secret.UpdatedAt)},
+		{Name: "url", Value: secret.URL},
+		{Name: "tags", Value: secret.Tags},
+		{Name: "notes", Value: secret.Notes},
+		{Name: "expires_at", Value: formatTimestampPtr(secret.ExpiresAt)},
+		{Name: "rotate_every", Value: secret.RotateEvery},
+		{Name: "due_at", Value: formatDueAt(secret)},
+	}
+}
+
+// formatTimestampPtr formats an optional time as RFC 3339
+func formatTimestampPtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatTimestamp(*t)
+}
+
+// formatDueAt returns when the secret is due for rotation as RFC 3339, or ""
+func formatDueAt(secret storage.Secret) string {
+	due, ok := secret.DueAt()
+	if !ok {
+		return ""
+	}
+	return formatTimestamp(due)
+}
+
+// formatTimestamp formats t as RFC 3339, or "" when it is unknown
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatTime formats t for people, in local time
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Local().Format("2006-01-02 15:04:05 MST")
+}
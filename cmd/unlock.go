@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Unlock the vault in the background agent",
+	Long: `Unlock Vault
+
+Unlock the vault once and hand its derived key to the running agent,
+so later commands do not prompt for the master password.
+
+EXAMPLES:
+  eval "$(uzp agent)"
+  uzp unlock
+  uzp --profile work unlock
+
+NOTE:
+  The key is forgotten after the agent's idle or absolute timeout,
+  or immediately with 'uzp lock'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := requireAgent()
+		if err != nil {
+			return err
+		}
+
+		password, err := unlockVault()
+		if err != nil {
+			return err
+		}
+		clearBytes(password)
+
+		key, err := vault.Key()
+		if err != nil {
+			return err
+		}
+		defer clearBytes(key)
+
+		if err := client.Add(vault.Path(), key); err != nil {
+			return err
+		}
+
+		fmt.Printf("Unlocked %s in agent.\n", vault.Path())
+		return nil
+	},
+}
@@ -5,10 +5,16 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"syscall"
 
+	"github.com/hungnguyen18/uzp-cli/internal/generator"
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
+)
+
+var (
+	updateMeta     secretMeta
+	updateGenerate string
+	updateGen      generatorFlags
 )
 
 var updateCmd = &cobra.Command{
@@ -25,11 +31,32 @@ EXAMPLES:
   uzp update myapp/api_key
   uzp update backend/database_url
   uzp update auth/jwt_secret
+  uzp update myapp/api_key --tag prod --tag billing
+  uzp update myapp/api_key --note ""          Clear the note
+  uzp update myapp/db_password --generate     Rotate to a generated password
+
+METADATA:
+  --type totp          TOTP seed or otpauth:// URI (see uzp otp)
+  --note TEXT          Note stored with the secret
+  --tag TAG            Tags (repeatable, replaces existing tags)
+  --url URL            URL of the service
+  --expires DATE       Expiry date (YYYY-MM-DD) or interval from now (90d)
+  --rotate-every 90d   Rotation interval (see uzp due)
+  With any of these, an empty new value keeps the current value; an
+  empty flag (e.g. --note "") clears the field.
+
+GENERATE:
+  --generate[=MODE]    Generate the new value instead of prompting for it
+                       (password, passphrase, hex, base64, uuid); takes
+                       the options of uzp generate, e.g. --length 32
 
 WORKFLOW:
   1. Specify secret path
   2. Enter new value (hidden)
-  3. Confirm update`,
+  3. Confirm update
+
+When stdin is not a terminal, the value and the confirmation are read
+line by line from stdin. For scripts, prefer 'uzp set --yes'.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Validate arguments FIRST before prompting for password
@@ -37,14 +64,17 @@ WORKFLOW:
 			return fmt.Errorf("usage: uzp update <project/key>")
 		}
 
-		// Parse project/key
-		parts := strings.Split(args[0], "/")
-		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-			return fmt.Errorf("invalid format. use: project/key")
+		project, key, err := parseSecretPath(args[0])
+		if err != nil {
+			return err
+		}
+		if err := updateMeta.validate(); err != nil {
+			return err
+		}
+		genOpts, err := updateGen.generateOption(cmd, updateGenerate)
+		if err != nil {
+			return err
 		}
-
-		project := parts[0]
-		key := parts[1]
 
 		// Check if vault is unlocked, prompt for password if needed
 		if err := ensureVaultUnlocked(); err != nil {
@@ -54,34 +84,50 @@ WORKFLOW:
 		// Check if secret exists
 		currentValue, err := vault.Get(project, key)
 		if err != nil {
-			return fmt.Errorf("secret not found: %s/%s", project, key)
+			return err
 		}
 
 		// Show current secret info (without value for security)
 		fmt.Printf("Updating: %s/%s\n", project, key)
 
 		// Get new value
-		fmt.Print("New value: ")
-		valueBytes, err := term.ReadPassword(int(syscall.Stdin))
-		if err != nil {
-			return fmt.Errorf("failed to read value: %w", err)
+		metaChanged := updateMeta.changed(cmd)
+		prompt := "New value: "
+		if metaChanged {
+			prompt = "New value (empty to keep): "
+		}
+
+		reader := bufio.NewReader(os.Stdin)
+		var valueBytes []byte
+		if genOpts != nil {
+			valueBytes, err = generator.Generate(*genOpts)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Generated a new value (%s).\n", describeGenerated(*genOpts, valueBytes))
+		} else {
+			valueBytes, err = readHidden(reader, prompt)
+			if err != nil {
+				return fmt.Errorf("failed to read value: %w", err)
+			}
 		}
-		fmt.Println() // New line after password
 		newValue := string(valueBytes)
 
 		// Validate new value
 		if newValue == "" {
-			return fmt.Errorf("value cannot be empty")
+			if !metaChanged {
+				return fmt.Errorf("value cannot be empty")
+			}
+			newValue = currentValue
 		}
 
 		// Check if new value is the same as current
-		if newValue == currentValue {
+		if newValue == currentValue && !metaChanged {
 			fmt.Println("No changes made.")
 			return nil
 		}
 
 		// Confirm update
-		reader := bufio.NewReader(os.Stdin)
 		fmt.Print("Update? (y/N): ")
 		response, err := reader.ReadString('\n')
 		if err != nil {
@@ -95,7 +141,11 @@ WORKFLOW:
 		}
 
 		// Update secret
-		if err := vault.Add(project, key, newValue); err != nil {
+		err = vault.Put(project, key, func(secret *storage.Secret) error {
+			secret.Value = newValue
+			return updateMeta.apply(cmd, secret)
+		})
+		if err != nil {
 			return fmt.Errorf("failed to update secret: %w", err)
 		}
 
@@ -109,3 +159,8 @@ WORKFLOW:
 		return nil
 	},
 }
+
+func init() {
+	updateMeta.addFlags(updateCmd)
+	updateGen.addGenerateFlags(updateCmd, &updateGenerate)
+}
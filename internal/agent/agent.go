@@ -0,0 +1,271 @@
+package agent
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SocketEnv names the environment variable holding the agent socket path
+const SocketEnv = "UZP_AGENT_SOCK"
+
+// Request operations understood by the agent
+const (
+	opAdd       = "add"
+	opGet       = "get"
+	opRemove    = "remove"
+	opRemoveAll = "remove-all"
+	opStatus    = "status"
+	opStop      = "stop"
+)
+
+// request is a single message sent by the client
+type request struct {
+	Op    string `json:"op"`
+	Vault string `json:"vault,omitempty"`
+	Key   string `json:"key,omitempty"` // Base64 encoded vault key
+}
+
+// response is the agent's reply to a request
+type response struct {
+	OK     bool          `json:"ok"`
+	Error  string        `json:"error,omitempty"`
+	Key    string        `json:"key,omitempty"`
+	Vaults []VaultStatus `json:"vaults,omitempty"`
+}
+
+// VaultStatus describes a vault whose key is held by the agent
+type VaultStatus struct {
+	Path       string    `json:"path"`
+	UnlockedAt time.Time `json:"unlocked_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// entry is a cached vault key
+type entry struct {
+	key        []byte
+	unlockedAt time.Time
+	lastUsed   time.Time
+}
+
+// Server holds derived vault keys in memory and serves them over a Unix socket
+type Server struct {
+	socketPath  string
+	idleTimeout time.Duration
+	maxLifetime time.Duration
+
+	mu       sync.Mutex
+	entries  map[string]*entry
+	listener net.Listener
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewServer creates an agent that forgets a key after idleTimeout without
+// use, or maxLifetime after it was added, whichever comes first
+func NewServer(socketPath string, idleTimeout, maxLifetime time.Duration) *Server {
+	return &Server{
+		socketPath:  socketPath,
+		idleTimeout: idleTimeout,
+		maxLifetime: maxLifetime,
+		entries:     make(map[string]*entry),
+		done:        make(chan struct{}),
+	}
+}
+
+// Listen creates the socket, readable and writable by the owner only
+func (s *Server) Listen() error {
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0700); err != nil {
+		return fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	// Refuse to replace a live agent, but clean up a stale socket
+	if conn, err := net.Dial("unix", s.socketPath); err == nil {
+		conn.Close()
+		return fmt.Errorf("an agent is already running on %s", s.socketPath)
+	}
+	if err := os.Remove(s.socketPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	listener, err := listenPrivate(s.socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.socketPath, err)
+	}
+
+	// Already created with mode 0600; kept as a safeguard
+	if err := os.Chmod(s.socketPath, 0600); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to set socket permissions: %w", err)
+	}
+
+	s.listener = listener
+	return nil
+}
+
+// Serve handles connections until Stop is called
+func (s *Server) Serve() error {
+	go s.expireLoop()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return nil
+			default:
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		go s.handle(conn)
+	}
+}
+
+// Stop closes the socket and wipes every cached key
+func (s *Server) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		if s.listener != nil {
+			s.listener.Close()
+		}
+		os.Remove(s.socketPath)
+
+		s.mu.Lock()
+		for path := range s.entries {
+			s.removeLocked(path)
+		}
+		s.mu.Unlock()
+	})
+}
+
+// handle serves a single request on conn
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		_ = json.NewEncoder(conn).Encode(response{Error: "invalid request"})
+		return
+	}
+
+	resp := s.dispatch(req)
+	_ = json.NewEncoder(conn).Encode(resp)
+
+	if req.Op == opStop {
+		s.Stop()
+	}
+}
+
+// dispatch executes a request against the key cache
+func (s *Server) dispatch(req request) response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	switch req.Op {
+	case opAdd:
+		key, err := base64.StdEncoding.DecodeString(req.Key)
+		if err != nil || req.Vault == "" {
+			return response{Error: "invalid key or vault path"}
+		}
+		s.removeLocked(req.Vault)
+		s.entries[req.Vault] = &entry{key: key, unlockedAt: now, lastUsed: now}
+		return response{OK: true}
+
+	case opGet:
+		e, ok := s.entries[req.Vault]
+		if !ok || s.expired(e, now) {
+			s.removeLocked(req.Vault)
+			return response{Error: "vault is not unlocked in the agent"}
+		}
+		e.lastUsed = now
+		return response{OK: true, Key: base64.StdEncoding.EncodeToString(e.key)}
+
+	case opRemove:
+		s.removeLocked(req.Vault)
+		return response{OK: true}
+
+	case opRemoveAll:
+		for path := range s.entries {
+			s.removeLocked(path)
+		}
+		return response{OK: true}
+
+	case opStatus:
+		vaults := []VaultStatus{}
+		for path, e := range s.entries {
+			if s.expired(e, now) {
+				continue
+			}
+			vaults = append(vaults, VaultStatus{Path: path, UnlockedAt: e.unlockedAt, ExpiresAt: s.expiresAt(e)})
+		}
+		sort.Slice(vaults, func(i, j int) bool { return vaults[i].Path < vaults[j].Path })
+		return response{OK: true, Vaults: vaults}
+
+	case opStop:
+		return response{OK: true}
+
+	default:
+		return response{Error: fmt.Sprintf("unknown operation: %s", req.Op)}
+	}
+}
+
+// expireLoop periodically wipes keys that passed their timeouts
+func (s *Server) expireLoop() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for path, e := range s.entries {
+				if s.expired(e, now) {
+					s.removeLocked(path)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// expiresAt returns when e will be forgotten if it is not used again
+func (s *Server) expiresAt(e *entry) time.Time {
+	expiry := e.lastUsed.Add(s.idleTimeout)
+	if absolute := e.unlockedAt.Add(s.maxLifetime); absolute.Before(expiry) {
+		expiry = absolute
+	}
+	return expiry
+}
+
+// expired reports whether e passed its idle or absolute timeout
+func (s *Server) expired(e *entry, now time.Time) bool {
+	return !now.Before(s.expiresAt(e))
+}
+
+// removeLocked wipes and forgets the key for path; s.mu must be held
+func (s *Server) removeLocked(path string) {
+	e, ok := s.entries[path]
+	if !ok {
+		return
+	}
+	for i := range e.key {
+		e.key[i] = 0
+	}
+	delete(s.entries, path)
+}
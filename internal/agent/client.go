@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Client talks to a running agent
+type Client struct {
+	socketPath string
+}
+
+// NewClient creates a client for the agent listening on socketPath
+func NewClient(socketPath string) *Client {
+	return &Client{socketPath: socketPath}
+}
+
+// ClientFromEnv returns a client for UZP_AGENT_SOCK, or nil if it is unset
+func ClientFromEnv() *Client {
+	socketPath := os.Getenv(SocketEnv)
+	if socketPath == "" {
+		return nil
+	}
+	return NewClient(socketPath)
+}
+
+// Add hands the key of the vault at vaultPath to the agent
+func (c *Client) Add(vaultPath string, key []byte) error {
+	path, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.call(request{Op: opAdd, Vault: path, Key: base64.StdEncoding.EncodeToString(key)})
+	return err
+}
+
+// Get returns the key the agent holds for the vault at vaultPath.
+// The caller is responsible for clearing it.
+func (c *Client) Get(vaultPath string) ([]byte, error) {
+	path, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.call(request{Op: opGet, Vault: path})
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(resp.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key from agent: %w", err)
+	}
+	return key, nil
+}
+
+// Remove makes the agent forget the key of the vault at vaultPath
+func (c *Client) Remove(vaultPath string) error {
+	path, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.call(request{Op: opRemove, Vault: path})
+	return err
+}
+
+// RemoveAll makes the agent forget every key
+func (c *Client) RemoveAll() error {
+	_, err := c.call(request{Op: opRemoveAll})
+	return err
+}
+
+// Status lists the vaults currently unlocked in the agent
+func (c *Client) Status() ([]VaultStatus, error) {
+	resp, err := c.call(request{Op: opStatus})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Vaults, nil
+}
+
+// Stop shuts the agent down, wiping all keys
+func (c *Client) Stop() error {
+	_, err := c.call(request{Op: opStop})
+	return err
+}
+
+// call sends a request and waits for the agent's response
+func (c *Client) call(req request) (*response, error) {
+	conn, err := net.DialTimeout("unix", c.socketPath, 2*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to agent at %s: %w", c.socketPath, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("failed to send request to agent: %w", err)
+	}
+
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to read response from agent: %w", err)
+	}
+
+	if !resp.OK {
+		return nil, fmt.Errorf("agent: %s", resp.Error)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package agent
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenPrivate creates a Unix socket only the owner can connect to. The
+// umask is tightened around the bind, so the socket never exists with
+// looser permissions, not even before a chmod.
+func listenPrivate(path string) (net.Listener, error) {
+	previous := syscall.Umask(0o177)
+	defer syscall.Umask(previous)
+
+	return net.Listen("unix", path)
+}
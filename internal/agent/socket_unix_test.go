@@ -0,0 +1,34 @@
+//go:build !windows
+
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestListenPrivateIgnoresUmask(t *testing.T) {
+	// A permissive umask must not leak into the socket mode
+	previous := syscall.Umask(0)
+	defer syscall.Umask(previous)
+
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := listenPrivate(path)
+	if err != nil {
+		t.Fatalf("listenPrivate: %v", err)
+	}
+	defer listener.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket mode = %o, want 600", perm)
+	}
+	if got := syscall.Umask(previous); got != 0 {
+		t.Errorf("umask after listen = %o, want it restored to 0", got)
+	}
+}
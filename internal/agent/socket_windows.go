@@ -0,0 +1,11 @@
+//go:build windows
+
+package agent
+
+import "net"
+
+// listenPrivate creates a Unix socket; on Windows access is governed by
+// the ACL of the socket directory, which is created for the owner only
+func listenPrivate(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
@@ -8,24 +8,14 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
-
-	"golang.org/x/crypto/scrypt"
 )
 
 const (
 	saltSize  = 32
 	keySize   = 32 // AES-256
 	nonceSize = 12 // GCM standard nonce size
-	scryptN   = 32768
-	scryptR   = 8
-	scryptP   = 1
 )
 
-// DeriveKey derives an encryption key from a password using scrypt
-func DeriveKey(password string, salt []byte) ([]byte, error) {
-	return scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, keySize)
-}
-
 // GenerateSalt generates a random salt
 func GenerateSalt() ([]byte, error) {
 	salt := make([]byte, saltSize)
@@ -35,8 +25,10 @@ func GenerateSalt() ([]byte, error) {
 	return salt, nil
 }
 
-// Encrypt encrypts data using AES-256-GCM
-func Encrypt(plaintext []byte, key []byte) ([]byte, error) {
+// Encrypt encrypts data using AES-256-GCM.
+// additionalData is authenticated but not encrypted; the same bytes must be
+// passed to Decrypt. It may be nil.
+func Encrypt(plaintext []byte, key []byte, additionalData []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cipher: %w", err)
@@ -52,12 +44,12 @@ func Encrypt(plaintext []byte, key []byte) ([]byte, error) {
 		return nil, fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
-	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+	ciphertext := gcm.Seal(nonce, nonce, plaintext, additionalData)
 	return ciphertext, nil
 }
 
-// Decrypt decrypts data using AES-256-GCM
-func Decrypt(ciphertext []byte, key []byte) ([]byte, error) {
+// Decrypt decrypts data using AES-256-GCM, verifying additionalData
+func Decrypt(ciphertext []byte, key []byte, additionalData []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cipher: %w", err)
@@ -73,7 +65,7 @@ func Decrypt(ciphertext []byte, key []byte) ([]byte, error) {
 	}
 
 	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt: %w", err)
 	}
@@ -81,12 +73,6 @@ func Decrypt(ciphertext []byte, key []byte) ([]byte, error) {
 	return plaintext, nil
 }
 
-// HashPassword creates a SHA-256 hash of the password for verification
-func HashPassword(password string) string {
-	hash := sha256.Sum256([]byte(password))
-	return base64.StdEncoding.EncodeToString(hash[:])
-}
-
 // HashData creates a SHA-256 hash of arbitrary data
 func HashData(data []byte) string {
 	hash := sha256.Sum256(data)
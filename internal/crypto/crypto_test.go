@@ -0,0 +1,74 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := GenerateSalt() // 32 random bytes, the size of an AES-256 key
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestEncryptDecryptWithAAD(t *testing.T) {
+	key := testKey(t)
+	plaintext := []byte(`{"projects":{}}`)
+	aad := []byte(`{"format":"uzp-vault","version":3}`)
+
+	ciphertext, err := Encrypt(plaintext, key, aad)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	got, err := Decrypt(ciphertext, key, aad)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt = %q, want %q", got, plaintext)
+	}
+}
+
+func TestDecryptRejectsAADMismatch(t *testing.T) {
+	key := testKey(t)
+	ciphertext, err := Encrypt([]byte("secret"), key, []byte("version 7"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	for name, aad := range map[string][]byte{
+		"modified": []byte("version 6"),
+		"missing":  nil,
+	} {
+		if _, err := Decrypt(ciphertext, key, aad); err == nil {
+			t.Errorf("%s AAD: Decrypt succeeded, want an error", name)
+		}
+	}
+}
+
+func TestDecryptRejectsWrongKeyAndModifiedData(t *testing.T) {
+	key := testKey(t)
+	aad := []byte("header")
+	ciphertext, err := Encrypt([]byte("secret"), key, aad)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	if _, err := Decrypt(ciphertext, testKey(t), aad); err == nil {
+		t.Error("wrong key: Decrypt succeeded, want an error")
+	}
+
+	modified := append([]byte(nil), ciphertext...)
+	modified[len(modified)-1] ^= 0x01
+	if _, err := Decrypt(modified, key, aad); err == nil {
+		t.Error("modified ciphertext: Decrypt succeeded, want an error")
+	}
+
+	if _, err := Decrypt(ciphertext[:4], key, aad); err == nil {
+		t.Error("short ciphertext: Decrypt succeeded, want an error")
+	}
+}
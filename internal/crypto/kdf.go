@@ -0,0 +1,217 @@
+package crypto
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Supported key derivation functions
+const (
+	KDFScrypt   = "scrypt"
+	KDFArgon2id = "argon2id"
+)
+
+const (
+	// Parameters used by vaults created before the KDF was stored in the header
+	legacyScryptN = 32768
+	legacyScryptR = 8
+	legacyScryptP = 1
+
+	// Defaults for new vaults (RFC 9106 second recommended option)
+	defaultArgon2Time    = 3
+	defaultArgon2Memory  = 64 * 1024 // KiB
+	defaultArgon2Threads = 4
+
+	// Upper bounds so a crafted vault header cannot exhaust the machine
+	maxArgon2Time   = 100
+	maxArgon2Memory = 4 * 1024 * 1024 // KiB
+	maxScryptMemory = 2 << 30         // bytes
+)
+
+// KDFParams describes how the vault key is derived from the master password
+type KDFParams struct {
+	Name string `json:"name"`
+
+	// scrypt
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+
+	// argon2id
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"` // KiB
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// DefaultKDFParams returns the parameters used for new vaults
+func DefaultKDFParams() KDFParams {
+	return KDFParams{
+		Name:    KDFArgon2id,
+		Time:    defaultArgon2Time,
+		Memory:  defaultArgon2Memory,
+		Threads: defaultArgon2Threads,
+	}
+}
+
+// LegacyKDFParams returns the fixed scrypt parameters of older vaults
+func LegacyKDFParams() KDFParams {
+	return KDFParams{
+		Name: KDFScrypt,
+		N:    legacyScryptN,
+		R:    legacyScryptR,
+		P:    legacyScryptP,
+	}
+}
+
+// String returns a short human-readable description of the parameters
+func (p KDFParams) String() string {
+	switch p.Name {
+	case KDFScrypt:
+		return fmt.Sprintf("scrypt (N=%d, r=%d, p=%d)", p.N, p.R, p.P)
+	case KDFArgon2id:
+		return fmt.Sprintf("argon2id (time=%d, memory=%d MiB, threads=%d)", p.Time, p.Memory/1024, p.Threads)
+	default:
+		return p.Name
+	}
+}
+
+// Validate checks that the parameters are usable and within safe bounds
+func (p KDFParams) Validate() error {
+	switch p.Name {
+	case KDFScrypt:
+		if p.N <= 1 || p.N&(p.N-1) != 0 {
+			return fmt.Errorf("scrypt N must be a power of two greater than 1")
+		}
+		if p.R < 1 || p.P < 1 {
+			return fmt.Errorf("scrypt r and p must be at least 1")
+		}
+		if uint64(p.N)*uint64(p.R)*128 > maxScryptMemory {
+			return fmt.Errorf("scrypt parameters exceed the memory limit")
+		}
+	case KDFArgon2id:
+		if p.Time < 1 || p.Time > maxArgon2Time {
+			return fmt.Errorf("argon2id time must be between 1 and %d", maxArgon2Time)
+		}
+		if p.Threads < 1 {
+			return fmt.Errorf("argon2id threads must be at least 1")
+		}
+		if p.Memory < 8*uint32(p.Threads) || p.Memory > maxArgon2Memory {
+			return fmt.Errorf("argon2id memory must be between %d KiB and %d KiB", 8*uint32(p.Threads), maxArgon2Memory)
+		}
+	default:
+		return fmt.Errorf("unsupported key derivation function: %q", p.Name)
+	}
+	return nil
+}
+
+// DeriveKey derives an encryption key from a password with the given KDF
+func DeriveKey(password string, salt []byte, params KDFParams) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch params.Name {
+	case KDFScrypt:
+		return scrypt.Key([]byte(password), salt, params.N, params.R, params.P, keySize)
+	default:
+		return argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, keySize), nil
+	}
+}
+
+// BenchmarkKDF measures how long deriving a key with params takes
+func BenchmarkKDF(params KDFParams) (time.Duration, error) {
+	salt, err := GenerateSalt()
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	key, err := DeriveKey("uzp-benchmark", salt, params)
+	if err != nil {
+		return 0, err
+	}
+	elapsed := time.Since(start)
+
+	for i := range key {
+		key[i] = 0
+	}
+	return elapsed, nil
+}
+
+// CalibrateKDF returns the strongest parameters for the named KDF whose
+// derivation takes roughly target on this machine, and the measured time
+func CalibrateKDF(name string, target time.Duration) (KDFParams, time.Duration, error) {
+	switch name {
+	case KDFScrypt:
+		return calibrateScrypt(target)
+	case KDFArgon2id:
+		return calibrateArgon2id(target)
+	default:
+		return KDFParams{}, 0, fmt.Errorf("unsupported key derivation function: %q", name)
+	}
+}
+
+// calibrateScrypt doubles N until a derivation reaches the target time
+func calibrateScrypt(target time.Duration) (KDFParams, time.Duration, error) {
+	params := KDFParams{Name: KDFScrypt, N: 1 << 14, R: 8, P: 1}
+
+	elapsed, err := BenchmarkKDF(params)
+	if err != nil {
+		return KDFParams{}, 0, err
+	}
+
+	for elapsed < target {
+		next := params
+		next.N *= 2
+		if next.Validate() != nil {
+			break
+		}
+
+		nextElapsed, err := BenchmarkKDF(next)
+		if err != nil {
+			return KDFParams{}, 0, err
+		}
+		if nextElapsed > target*3/2 {
+			break
+		}
+		params, elapsed = next, nextElapsed
+	}
+
+	return params, elapsed, nil
+}
+
+// calibrateArgon2id keeps the default memory and raises the number of
+// passes until a derivation reaches the target time
+func calibrateArgon2id(target time.Duration) (KDFParams, time.Duration, error) {
+	threads := runtime.NumCPU()
+	if threads > defaultArgon2Threads {
+		threads = defaultArgon2Threads
+	}
+	params := KDFParams{Name: KDFArgon2id, Time: 1, Memory: defaultArgon2Memory, Threads: uint8(threads)}
+
+	elapsed, err := BenchmarkKDF(params)
+	if err != nil {
+		return KDFParams{}, 0, err
+	}
+
+	// Passes scale linearly, so estimate from a single one
+	if elapsed <= 0 {
+		return params, elapsed, nil
+	}
+	if passes := uint32(target / elapsed); passes > 1 {
+		if passes > maxArgon2Time {
+			passes = maxArgon2Time
+		}
+		params.Time = passes
+
+		if elapsed, err = BenchmarkKDF(params); err != nil {
+			return KDFParams{}, 0, err
+		}
+	}
+
+	return params, elapsed, nil
+}
@@ -0,0 +1,229 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Entry is a single KEY=value assignment from a dotenv file
+type Entry struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// Parse reads dotenv syntax and returns the assignments in file order.
+//
+// Supported syntax:
+//   - blank lines and # comments, including trailing comments after values
+//   - an optional "export " prefix
+//   - unquoted values, trimmed of surrounding whitespace
+//   - 'single' and `backtick` quoted values, taken literally
+//   - "double" quoted values with \n, \r, \t, \", \\ and \$ escapes
+//   - quoted values spanning multiple lines
+//
+// When a key appears more than once, the last value wins.
+func Parse(r io.Reader) ([]Entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dotenv file: %w", err)
+	}
+
+	p := &parser{src: strings.ReplaceAll(string(data), "\r\n", "\n"), line: 1}
+
+	entries := []Entry{}
+	index := make(map[string]int)
+	for {
+		entry, ok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		if i, exists := index[entry.Key]; exists {
+			entries[i] = entry
+			continue
+		}
+		index[entry.Key] = len(entries)
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// parser walks the source one assignment at a time
+type parser struct {
+	src  string
+	pos  int
+	line int
+}
+
+// next returns the next assignment, or ok=false at end of input
+func (p *parser) next() (Entry, bool, error) {
+	for p.pos < len(p.src) {
+		p.skipSpaces()
+
+		// Blank line or comment
+		if p.pos >= len(p.src) {
+			break
+		}
+		if c := p.src[p.pos]; c == '\n' || c == '#' {
+			p.skipLine()
+			continue
+		}
+
+		line := p.line
+		rest := p.src[p.pos:]
+		if strings.HasPrefix(rest, "export ") || strings.HasPrefix(rest, "export\t") {
+			p.pos += len("export")
+			p.skipSpaces()
+		}
+
+		key := p.readKey()
+		if key == "" {
+			return Entry{}, false, fmt.Errorf("line %d: expected KEY=value", line)
+		}
+
+		p.skipSpaces()
+		if p.pos >= len(p.src) || p.src[p.pos] != '=' {
+			return Entry{}, false, fmt.Errorf("line %d: missing '=' after %s", line, key)
+		}
+		p.pos++
+		p.skipSpaces()
+
+		value, err := p.readValue(line)
+		if err != nil {
+			return Entry{}, false, err
+		}
+
+		return Entry{Key: key, Value: value, Line: line}, true, nil
+	}
+
+	return Entry{}, false, nil
+}
+
+// readKey reads a variable name
+func (p *parser) readKey() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' ||
+			(p.pos > start && ((c >= '0' && c <= '9') || c == '.' || c == '-')) {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.src[start:p.pos]
+}
+
+// readValue reads a quoted or unquoted value and the rest of its line
+func (p *parser) readValue(line int) (string, error) {
+	if p.pos >= len(p.src) || p.src[p.pos] == '\n' {
+		p.skipLine()
+		return "", nil
+	}
+
+	switch quote := p.src[p.pos]; quote {
+	case '\'', '`':
+		p.pos++
+		end := strings.IndexByte(p.src[p.pos:], quote)
+		if end < 0 {
+			return "", fmt.Errorf("line %d: unterminated %c quote", line, quote)
+		}
+		value := p.src[p.pos : p.pos+end]
+		p.line += strings.Count(value, "\n")
+		p.pos += end + 1
+		return value, p.finishLine(line)
+
+	case '"':
+		p.pos++
+		var b strings.Builder
+		for {
+			if p.pos >= len(p.src) {
+				return "", fmt.Errorf("line %d: unterminated \" quote", line)
+			}
+			c := p.src[p.pos]
+			p.pos++
+
+			switch c {
+			case '"':
+				return b.String(), p.finishLine(line)
+			case '\n':
+				p.line++
+				b.WriteByte(c)
+			case '\\':
+				if p.pos >= len(p.src) {
+					return "", fmt.Errorf("line %d: unterminated \" quote", line)
+				}
+				escaped := p.src[p.pos]
+				p.pos++
+				switch escaped {
+				case 'n':
+					b.WriteByte('\n')
+				case 'r':
+					b.WriteByte('\r')
+				case 't':
+					b.WriteByte('\t')
+				case '"', '\\', '$', '\'', '`':
+					b.WriteByte(escaped)
+				default:
+					// Unknown escapes are kept as written
+					b.WriteByte('\\')
+					b.WriteByte(escaped)
+				}
+			default:
+				b.WriteByte(c)
+			}
+		}
+
+	default:
+		end := strings.IndexByte(p.src[p.pos:], '\n')
+		if end < 0 {
+			end = len(p.src) - p.pos
+		}
+		value := p.src[p.pos : p.pos+end]
+		p.pos += end
+		p.skipLine()
+
+		// A # preceded by whitespace starts a comment
+		for i := 1; i < len(value); i++ {
+			if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+				value = value[:i]
+				break
+			}
+		}
+		return strings.TrimSpace(value), nil
+	}
+}
+
+// finishLine allows only whitespace or a comment after a closing quote
+func (p *parser) finishLine(line int) error {
+	p.skipSpaces()
+	if p.pos < len(p.src) && p.src[p.pos] != '\n' && p.src[p.pos] != '#' {
+		return fmt.Errorf("line %d: unexpected characters after closing quote", line)
+	}
+	p.skipLine()
+	return nil
+}
+
+// skipSpaces advances past spaces and tabs
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// skipLine advances past the end of the current line
+func (p *parser) skipLine() {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end < 0 {
+		p.pos = len(p.src)
+		return
+	}
+	p.pos += end + 1
+	p.line++
+}
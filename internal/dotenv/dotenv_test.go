@@ -0,0 +1,109 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Entry
+	}{
+		{
+			name:  "unquoted values are trimmed",
+			input: "A=1\nB =  two words  \nC=\n",
+			want:  []Entry{{"A", "1", 1}, {"B", "two words", 2}, {"C", "", 3}},
+		},
+		{
+			name:  "blank lines and comments",
+			input: "# header\n\n  # indented\nA=1\n",
+			want:  []Entry{{"A", "1", 4}},
+		},
+		{
+			name:  "inline comments",
+			input: "A=value # comment\nB=a#b\nC=\"x\" # comment\nD='y'\t# comment\n",
+			want:  []Entry{{"A", "value", 1}, {"B", "a#b", 2}, {"C", "x", 3}, {"D", "y", 4}},
+		},
+		{
+			name:  "export prefix",
+			input: "export A=1\nexport\tB=2\nexport_C=3\n",
+			want:  []Entry{{"A", "1", 1}, {"B", "2", 2}, {"export_C", "3", 3}},
+		},
+		{
+			name:  "single and backtick quotes are literal",
+			input: `A='it is $HOME \n # here'` + "\n" + "B=`say \"hi\"`\n",
+			want:  []Entry{{"A", `it is $HOME \n # here`, 1}, {"B", `say "hi"`, 2}},
+		},
+		{
+			name:  "double quote escapes",
+			input: `A="line1\nline2\ttab\r"` + "\n" + `B="say \"hi\" \\ \$HOME \'"` + "\n" + `C="keep \q"` + "\n",
+			want: []Entry{
+				{"A", "line1\nline2\ttab\r", 1},
+				{"B", `say "hi" \ $HOME '`, 2},
+				{"C", `keep \q`, 3},
+			},
+		},
+		{
+			name:  "multiline quoted values",
+			input: "A=\"first\nsecond\"\nB='one\ntwo'\nC=3\n",
+			want:  []Entry{{"A", "first\nsecond", 1}, {"B", "one\ntwo", 3}, {"C", "3", 5}},
+		},
+		{
+			name:  "CRLF line endings",
+			input: "A=1\r\nB=\"x\"\r\n",
+			want:  []Entry{{"A", "1", 1}, {"B", "x", 2}},
+		},
+		{
+			name:  "last value wins",
+			input: "A=1\nB=2\nA=3\n",
+			want:  []Entry{{"A", "3", 3}, {"B", "2", 2}},
+		},
+		{
+			name:  "no trailing newline",
+			input: "A=\"x\"",
+			want:  []Entry{{"A", "x", 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("entry %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"A=1\n=2\n", "line 2: expected KEY=value"},
+		{"1A=2\n", "line 1: expected KEY=value"},
+		{"A 1\n", "line 1: missing '=' after A"},
+		{"JUST_A_KEY\n", "line 1: missing '=' after JUST_A_KEY"},
+		{"A=\"open\nB=2\n", "line 1: unterminated \" quote"},
+		{"A='open\n", "line 1: unterminated ' quote"},
+		{"A=\"trailing\\", "line 1: unterminated \" quote"},
+		{"A=\"x\" y\n", "line 1: unexpected characters after closing quote"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader(tt.input))
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// Generation modes
+const (
+	ModePassword   = "password"
+	ModePassphrase = "passphrase"
+	ModeHex        = "hex"
+	ModeBase64     = "base64"
+	ModeUUID       = "uuid"
+)
+
+// Modes lists the supported generation modes
+var Modes = []string{ModePassword, ModePassphrase, ModeHex, ModeBase64, ModeUUID}
+
+// Defaults used when Options.Length is zero
+const (
+	DefaultPasswordLength = 24 // Characters
+	DefaultWords          = 6  // Passphrase words, about 77 bits
+	DefaultTokenBytes     = 32 // Random bytes for hex and base64 tokens
+)
+
+// Upper bounds on Options.Length, far beyond any useful secret
+const (
+	MaxPasswordLength = 1024
+	MaxWords          = 100
+	MaxTokenBytes     = 1024
+)
+
+// Character classes for passwords
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = "!#$%&()*+,-./:;<=>?@[]^_{}~"
+
+	// Characters easily confused with each other when read or typed
+	ambiguousChars = "0Oo1lI|"
+)
+
+// Options configures a generated value
+type Options struct {
+	Mode string
+
+	// Length is the number of characters for passwords, words for
+	// passphrases and random bytes for hex and base64 tokens. Zero uses
+	// the mode's default; UUIDs ignore it.
+	Length int
+
+	// Password character classes; at least one must be enabled and each
+	// enabled class appears at least once
+	Lower, Upper, Digits, Symbols bool
+	ExcludeAmbiguous              bool
+
+	Separator string // Between passphrase words
+}
+
+// DefaultOptions returns options for a password using all character classes
+func DefaultOptions() Options {
+	return Options{
+		Mode:      ModePassword,
+		Lower:     true,
+		Upper:     true,
+		Digits:    true,
+		Symbols:   true,
+		Separator: "-",
+	}
+}
+
+// Validate checks the mode and that Length is within its bounds
+func (o Options) Validate() error {
+	if o.Length < 0 {
+		return fmt.Errorf("length cannot be negative")
+	}
+
+	switch o.Mode {
+	case ModePassword, "":
+		if o.Length > MaxPasswordLength {
+			return fmt.Errorf("password length cannot exceed %d characters", MaxPasswordLength)
+		}
+	case ModePassphrase:
+		if o.Length > MaxWords {
+			return fmt.Errorf("passphrases cannot exceed %d words", MaxWords)
+		}
+	case ModeHex, ModeBase64:
+		if o.Length > MaxTokenBytes {
+			return fmt.Errorf("tokens cannot exceed %d random bytes", MaxTokenBytes)
+		}
+	case ModeUUID:
+	default:
+		return fmt.Errorf("unsupported mode: %s (use one of: %s)", o.Mode, strings.Join(Modes, ", "))
+	}
+	return nil
+}
+
+// Generate returns a new random value from crypto/rand. The caller should
+// clear it once stored.
+func Generate(opts Options) ([]byte, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch opts.Mode {
+	case ModePassword, "":
+		return password(opts)
+	case ModePassphrase:
+		return passphrase(opts)
+	case ModeHex:
+		return token(opts.Length, func(b []byte) []byte {
+			out := make([]byte, hex.EncodedLen(len(b)))
+			hex.Encode(out, b)
+			return out
+		})
+	case ModeBase64:
+		return token(opts.Length, func(b []byte) []byte {
+			out := make([]byte, base64.RawURLEncoding.EncodedLen(len(b)))
+			base64.RawURLEncoding.Encode(out, b)
+			return out
+		})
+	case ModeUUID:
+		return uuid()
+	default:
+		return nil, fmt.Errorf("unsupported mode: %s (use one of: %s)", opts.Mode, strings.Join(Modes, ", "))
+	}
+}
+
+// password picks characters uniformly from the enabled classes, with at
+// least one character from each
+func password(opts Options) ([]byte, error) {
+	var classes []string
+	for _, class := range []struct {
+		enabled bool
+		chars   string
+	}{
+		{opts.Lower, lowerChars},
+		{opts.Upper, upperChars},
+		{opts.Digits, digitChars},
+		{opts.Symbols, symbolChars},
+	} {
+		if !class.enabled {
+			continue
+		}
+		chars := class.chars
+		if opts.ExcludeAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ambiguousChars, r) {
+					return -1
+				}
+				return r
+			}, chars)
+		}
+		classes = append(classes, chars)
+	}
+	if len(classes) == 0 {
+		return nil, fmt.Errorf("no character classes enabled")
+	}
+
+	length := opts.Length
+	if length == 0 {
+		length = DefaultPasswordLength
+	}
+	if length < len(classes) {
+		return nil, fmt.Errorf("length must be at least %d to include every character class", len(classes))
+	}
+
+	all := strings.Join(classes, "")
+	out := make([]byte, length)
+	for i := range out {
+		chars := all
+		if i < len(classes) {
+			chars = classes[i]
+		}
+		n, err := randInt(len(chars))
+		if err != nil {
+			clear(out)
+			return nil, err
+		}
+		out[i] = chars[n]
+	}
+
+	// Move the per-class characters to random positions
+	for i := len(out) - 1; i > 0; i-- {
+		j, err := randInt(i + 1)
+		if err != nil {
+			clear(out)
+			return nil, err
+		}
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return out, nil
+}
+
+// passphrase joins words picked from the EFF large word list
+func passphrase(opts Options) ([]byte, error) {
+	words := opts.Length
+	if words == 0 {
+		words = DefaultWords
+	}
+
+	list := wordList()
+	var buf bytes.Buffer
+	buf.Grow(words * (9 + len(opts.Separator))) // Longest word is 9 letters; growing would leave copies behind
+	for i := 0; i < words; i++ {
+		n, err := randInt(len(list))
+		if err != nil {
+			clear(buf.Bytes())
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString(opts.Separator)
+		}
+		buf.WriteString(list[n])
+	}
+	return buf.Bytes(), nil
+}
+
+// token encodes length random bytes
+func token(length int, encode func([]byte) []byte) ([]byte, error) {
+	if length == 0 {
+		length = DefaultTokenBytes
+	}
+
+	b := make([]byte, length)
+	defer clear(b)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return encode(b), nil
+}
+
+// uuid returns a random (version 4) UUID
+func uuid() ([]byte, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40 // Version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+
+	return []byte(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])), nil
+}
+
+// randInt returns a uniform random integer in [0, n)
+func randInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return int(v.Int64()), nil
+}
+
+// EFF's large word list for dice-generated passphrases (7776 words,
+// about 12.9 bits each):
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases
+//
+//go:embed eff_large_wordlist.txt
+var effLargeWordList string
+
+// wordList returns the words of the embedded list, without dice numbers
+var wordList = sync.OnceValue(func() []string {
+	words := make([]string, 0, 7776)
+	scanner := bufio.NewScanner(strings.NewReader(effLargeWordList))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			words = append(words, fields[1])
+		}
+	}
+	return words
+})
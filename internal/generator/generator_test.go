@@ -0,0 +1,174 @@
+package generator
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestPasswordIncludesEveryClass(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Length = 4
+
+	// With one character per class, a missing class would show up quickly
+	for i := 0; i < 200; i++ {
+		value, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if len(value) != 4 {
+			t.Fatalf("len = %d, want 4", len(value))
+		}
+		for _, chars := range []string{lowerChars, upperChars, digitChars, symbolChars} {
+			if !strings.ContainsAny(string(value), chars) {
+				t.Fatalf("%q has no character from %q", value, chars)
+			}
+		}
+	}
+}
+
+func TestPasswordDisabledClasses(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Length = 64
+	opts.Upper, opts.Symbols = false, false
+
+	value, err := Generate(opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if strings.ContainsAny(string(value), upperChars+symbolChars) {
+		t.Errorf("%q contains a disabled character class", value)
+	}
+
+	opts.Lower, opts.Digits = false, false
+	if _, err := Generate(opts); err == nil {
+		t.Error("Generate with no character classes succeeded")
+	}
+}
+
+func TestPasswordExcludeAmbiguous(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Length = MaxPasswordLength
+	opts.ExcludeAmbiguous = true
+
+	for i := 0; i < 10; i++ {
+		value, err := Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if strings.ContainsAny(string(value), "0Oo1lI|") {
+			t.Fatalf("%q contains an ambiguous character", value)
+		}
+	}
+}
+
+func TestPasswordLengthBounds(t *testing.T) {
+	opts := DefaultOptions()
+
+	value, err := Generate(opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(value) != DefaultPasswordLength {
+		t.Errorf("default length = %d, want %d", len(value), DefaultPasswordLength)
+	}
+
+	for _, length := range []int{-1, 3, MaxPasswordLength + 1} {
+		opts.Length = length
+		if _, err := Generate(opts); err == nil {
+			t.Errorf("Generate with length %d succeeded, want an error", length)
+		}
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	opts := Options{Mode: ModePassphrase, Length: 5, Separator: "."}
+	value, err := Generate(opts)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	words := strings.Split(string(value), ".")
+	if len(words) != 5 {
+		t.Fatalf("%q has %d words, want 5", value, len(words))
+	}
+	known := make(map[string]bool, len(wordList()))
+	for _, word := range wordList() {
+		known[word] = true
+	}
+	for _, word := range words {
+		if !known[word] {
+			t.Errorf("%q is not in the word list", word)
+		}
+	}
+
+	if n := len(wordList()); n != 7776 {
+		t.Errorf("word list has %d words, want 7776", n)
+	}
+
+	opts.Length = MaxWords + 1
+	if _, err := Generate(opts); err == nil {
+		t.Error("Generate with too many words succeeded")
+	}
+}
+
+func TestTokenLengths(t *testing.T) {
+	tests := []struct {
+		mode    string
+		length  int
+		wantLen int
+		decode  func(string) ([]byte, error)
+	}{
+		{ModeHex, 0, hex.EncodedLen(DefaultTokenBytes), hex.DecodeString},
+		{ModeHex, 16, 32, hex.DecodeString},
+		{ModeBase64, 0, base64.RawURLEncoding.EncodedLen(DefaultTokenBytes), base64.RawURLEncoding.DecodeString},
+		{ModeBase64, 48, 64, base64.RawURLEncoding.DecodeString},
+	}
+
+	for _, tt := range tests {
+		value, err := Generate(Options{Mode: tt.mode, Length: tt.length})
+		if err != nil {
+			t.Fatalf("Generate(%s, %d): %v", tt.mode, tt.length, err)
+		}
+		if len(value) != tt.wantLen {
+			t.Errorf("%s with %d bytes has length %d, want %d", tt.mode, tt.length, len(value), tt.wantLen)
+		}
+
+		decoded, err := tt.decode(string(value))
+		if err != nil {
+			t.Errorf("%s value %q does not decode: %v", tt.mode, value, err)
+		}
+		wantBytes := tt.length
+		if wantBytes == 0 {
+			wantBytes = DefaultTokenBytes
+		}
+		if len(decoded) != wantBytes {
+			t.Errorf("%s decodes to %d bytes, want %d", tt.mode, len(decoded), wantBytes)
+		}
+
+		if _, err := Generate(Options{Mode: tt.mode, Length: MaxTokenBytes + 1}); err == nil {
+			t.Errorf("Generate(%s) above the byte limit succeeded", tt.mode)
+		}
+	}
+}
+
+func TestUUID(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for i := 0; i < 50; i++ {
+		value, err := Generate(Options{Mode: ModeUUID})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if !pattern.Match(value) {
+			t.Fatalf("%q is not a version 4 UUID", value)
+		}
+	}
+}
+
+func TestUnknownMode(t *testing.T) {
+	if _, err := Generate(Options{Mode: "pin"}); err == nil {
+		t.Fatal("Generate with an unknown mode succeeded")
+	}
+}
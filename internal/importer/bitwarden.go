@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Bitwarden item types
+const (
+	bitwardenLogin      = 1
+	bitwardenSecureNote = 2
+)
+
+// bitwardenExport is the unencrypted JSON export of a Bitwarden vault
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []struct {
+		FolderID *string `json:"folderId"`
+		Type     int     `json:"type"`
+		Name     string  `json:"name"`
+		Notes    *string `json:"notes"`
+		Login    *struct {
+			Username *string `json:"username"`
+			Password *string `json:"password"`
+			Totp     *string `json:"totp"`
+			URIs     []struct {
+				URI *string `json:"uri"`
+			} `json:"uris"`
+		} `json:"login"`
+		Fields []struct {
+			Name  string  `json:"name"`
+			Value *string `json:"value"`
+		} `json:"fields"`
+	} `json:"items"`
+}
+
+// importBitwarden maps folders to projects and login fields to keys
+func importBitwarden(path, defaultProject string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse Bitwarden export: %w", err)
+	}
+	if export.Encrypted {
+		return nil, fmt.Errorf("encrypted Bitwarden exports are not supported; export as unencrypted JSON")
+	}
+
+	folders := make(map[string]string, len(export.Folders))
+	for _, folder := range export.Folders {
+		folders[folder.ID] = folder.Name
+	}
+
+	result := newResult()
+	for _, item := range export.Items {
+		project := defaultProject
+		if item.FolderID != nil && folders[*item.FolderID] != "" {
+			project = folders[*item.FolderID]
+		}
+
+		added := false
+		switch item.Type {
+		case bitwardenLogin:
+			if login := item.Login; login != nil {
+				added = result.add(project, item.Name, "username", deref(login.Username)) || added
+				added = result.add(project, item.Name, "password", deref(login.Password)) || added
+				added = result.addTOTP(project, item.Name, item.Name, deref(login.Totp)) || added
+				if len(login.URIs) > 0 {
+					added = result.add(project, item.Name, "url", deref(login.URIs[0].URI)) || added
+				}
+			}
+		case bitwardenSecureNote:
+			added = result.add(project, item.Name, "notes", deref(item.Notes)) || added
+		default:
+			result.skip("%s: unsupported item type (cards and identities are not imported)", item.Name)
+			continue
+		}
+
+		for _, field := range item.Fields {
+			added = result.add(project, item.Name, field.Name, deref(field.Value)) || added
+		}
+
+		if !added {
+			result.skip("%s: no values to import", item.Name)
+		}
+	}
+
+	return result, nil
+}
+
+// deref returns the string s points to, or "" for nil
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,43 @@
+package importer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+)
+
+func TestImportBitwarden(t *testing.T) {
+	result, err := Import(FormatBitwardenJSON, filepath.Join("testdata", "bitwarden.json"), "imported")
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	checkProject(t, result, "work_stuff", map[string]string{
+		"github_username":      "alice",
+		"github_password":      "hunter2",
+		"github_totp":          testOTPURI,
+		"github_url":           "https://github.com/login",
+		"github_recovery_code": "abcd-efgh",
+	})
+	checkTypes(t, result, "work_stuff", map[string]string{"github_totp": storage.TypeTOTP})
+
+	checkProject(t, result, "imported", map[string]string{
+		"steam_username":    "bob",
+		"server_note_notes": "rack 4",
+	})
+
+	// The steam:// code and the card are reported
+	if len(result.Skipped) != 2 {
+		t.Errorf("skipped = %q, want 2 entries", result.Skipped)
+	}
+}
+
+func TestImportBitwardenEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.json")
+	writeFile(t, path, `{"encrypted": true, "items": []}`)
+
+	if _, err := Import(FormatBitwardenJSON, path, "imported"); err == nil {
+		t.Fatal("Import of an encrypted export succeeded, want an error")
+	}
+}
@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+	"github.com/hungnguyen18/uzp-cli/internal/totp"
+)
+
+// Supported export formats
+const (
+	FormatBitwardenJSON  = "bitwarden-json"
+	FormatOnePasswordCSV = "1password-csv"
+	FormatKeePassXML     = "keepass-xml"
+	FormatPassDir        = "pass-dir"
+)
+
+// Formats lists the supported export formats
+var Formats = []string{FormatBitwardenJSON, FormatOnePasswordCSV, FormatKeePassXML, FormatPassDir}
+
+// Result holds the secrets found in an export, grouped by project
+type Result struct {
+	Secrets map[string]map[string]string
+	Types   map[string]map[string]string // Secret type by project and key, for keys that are not plain
+	Skipped []string                     // Human-readable reasons for items that were not imported
+}
+
+// Import reads the export at path in the given format. Items without a
+// folder, vault or group are placed in defaultProject.
+func Import(format, path, defaultProject string) (*Result, error) {
+	if defaultProject == "" {
+		return nil, fmt.Errorf("default project cannot be empty")
+	}
+
+	switch format {
+	case FormatBitwardenJSON:
+		return importBitwarden(path, defaultProject)
+	case FormatOnePasswordCSV:
+		return import1Password(path, defaultProject)
+	case FormatKeePassXML:
+		return importKeePass(path, defaultProject)
+	case FormatPassDir:
+		return importPass(path, defaultProject)
+	default:
+		return nil, fmt.Errorf("unsupported format: %s (use one of: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// newResult creates an empty result
+func newResult() *Result {
+	return &Result{Secrets: make(map[string]map[string]string)}
+}
+
+// add stores a secret under a sanitized project and item/field key.
+// Empty values are ignored; clashing keys get a numeric suffix.
+func (r *Result) add(project, item, field, value string) bool {
+	_, _, ok := r.put(project, item, field, value)
+	return ok
+}
+
+// addTyped stores a secret like add, recording its secret type
+func (r *Result) addTyped(project, item, field, value, secretType string) bool {
+	project, key, ok := r.put(project, item, field, value)
+	if !ok {
+		return false
+	}
+
+	if r.Types == nil {
+		r.Types = make(map[string]map[string]string)
+	}
+	if r.Types[project] == nil {
+		r.Types[project] = make(map[string]string)
+	}
+	r.Types[project][key] = secretType
+	return true
+}
+
+// addTOTP stores a TOTP seed or otpauth URI as a totp secret. Values that
+// do not parse are skipped with source in the reason, since 'uzp otp' could
+// not generate codes from them.
+func (r *Result) addTOTP(project, item, source, value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+
+	key, err := totp.Parse(value)
+	if err != nil {
+		r.skip("%s: %v", source, err)
+		return false
+	}
+	key.Clear()
+
+	return r.addTyped(project, item, "totp", value, storage.TypeTOTP)
+}
+
+// put stores a secret and returns the project and key it was stored under
+func (r *Result) put(project, item, field, value string) (string, string, bool) {
+	if value == "" {
+		return "", "", false
+	}
+
+	project = SanitizeName(project)
+	key := SanitizeName(item)
+	if field != "" {
+		key += "_" + SanitizeName(field)
+	}
+	if project == "" || key == "" {
+		return "", "", false
+	}
+
+	if r.Secrets[project] == nil {
+		r.Secrets[project] = make(map[string]string)
+	}
+
+	unique := key
+	for i := 2; ; i++ {
+		if _, exists := r.Secrets[project][unique]; !exists {
+			break
+		}
+		unique = fmt.Sprintf("%s_%d", key, i)
+	}
+
+	r.Secrets[project][unique] = value
+	return project, unique, true
+}
+
+// skip records an item that was not imported
+func (r *Result) skip(format string, args ...interface{}) {
+	r.Skipped = append(r.Skipped, fmt.Sprintf(format, args...))
+}
+
+// SanitizeName converts a folder, item or field name to a uzp project or
+// key name: lowercase letters, digits, '-' and '_'
+func SanitizeName(name string) string {
+	result := make([]byte, 0, len(name))
+	lastUnderscore := false
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			result = append(result, c+32)
+			lastUnderscore = false
+		case (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-':
+			result = append(result, c)
+			lastUnderscore = false
+		default:
+			if !lastUnderscore {
+				result = append(result, '_')
+				lastUnderscore = true
+			}
+		}
+	}
+
+	return strings.Trim(string(result), "_")
+}
@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+)
+
+const testOTPURI = "otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP&issuer=GitHub"
+
+// writeFile writes an export file for a test
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkProject compares the secrets imported into project with want
+func checkProject(t *testing.T, result *Result, project string, want map[string]string) {
+	t.Helper()
+	got := result.Secrets[project]
+	if len(got) != len(want) {
+		t.Errorf("%s: got keys %v, want %v", project, got, want)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s/%s = %q, want %q", project, key, got[key], value)
+		}
+	}
+}
+
+// checkTypes compares the secret types recorded for project with want
+func checkTypes(t *testing.T, result *Result, project string, want map[string]string) {
+	t.Helper()
+	got := result.Types[project]
+	if len(got) != len(want) {
+		t.Errorf("%s: got types %v, want %v", project, got, want)
+	}
+	for key, secretType := range want {
+		if got[key] != secretType {
+			t.Errorf("%s/%s type = %q, want %q", project, key, got[key], secretType)
+		}
+	}
+}
+
+func TestAddTOTP(t *testing.T) {
+	result := newResult()
+	if !result.addTOTP("web", "github", "GitHub", " "+testOTPURI+" ") {
+		t.Fatal("addTOTP rejected a valid otpauth URI")
+	}
+	if !result.addTOTP("web", "seed", "Seed", "JBSWY3DPEHPK3PXP") {
+		t.Fatal("addTOTP rejected a base32 seed")
+	}
+	if result.addTOTP("web", "steam", "Steam", "steam://ABC") {
+		t.Error("addTOTP accepted a value that is not TOTP")
+	}
+	if result.addTOTP("web", "empty", "Empty", "") {
+		t.Error("addTOTP accepted an empty value")
+	}
+
+	checkProject(t, result, "web", map[string]string{
+		"github_totp": testOTPURI,
+		"seed_totp":   "JBSWY3DPEHPK3PXP",
+	})
+	checkTypes(t, result, "web", map[string]string{
+		"github_totp": storage.TypeTOTP,
+		"seed_totp":   storage.TypeTOTP,
+	})
+	if len(result.Skipped) != 1 {
+		t.Errorf("skipped = %q, want only the steam value", result.Skipped)
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	for input, want := range map[string]string{
+		"GitHub":          "github",
+		"Work Stuff":      "work_stuff",
+		"  API--Key!! ":   "api--key",
+		"héllo":           "h_llo",
+		"a/b\\c":          "a_b_c",
+		"___":             "",
+		"Recovery Code 2": "recovery_code_2",
+	} {
+		if got := SanitizeName(input); got != want {
+			t.Errorf("SanitizeName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
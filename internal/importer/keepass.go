@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// keepassGroup is a group in a KeePass 2.x XML export
+type keepassGroup struct {
+	Name    string         `xml:"Name"`
+	Entries []keepassEntry `xml:"Entry"`
+	Groups  []keepassGroup `xml:"Group"`
+}
+
+// keepassEntry is an entry with its string fields
+type keepassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+}
+
+// keepassFields maps standard KeePass fields to key suffixes
+var keepassFields = map[string]string{
+	"UserName": "username",
+	"Password": "password",
+	"URL":      "url",
+	"Notes":    "notes",
+	"otp":      "totp",
+}
+
+// importKeePass maps groups to projects and entry fields to keys.
+// Nested groups are joined with '-'; the recycle bin is skipped.
+func importKeePass(path, defaultProject string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var export struct {
+		Root struct {
+			Groups []keepassGroup `xml:"Group"`
+		} `xml:"Root"`
+	}
+	if err := xml.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse KeePass XML: %w", err)
+	}
+
+	result := newResult()
+	for _, root := range export.Root.Groups {
+		// Entries in the top-level (database) group have no folder
+		importKeePassEntries(result, root.Entries, defaultProject)
+		for _, group := range root.Groups {
+			importKeePassGroup(result, group, nil)
+		}
+	}
+
+	return result, nil
+}
+
+// importKeePassGroup imports a group and its subgroups
+func importKeePassGroup(result *Result, group keepassGroup, parents []string) {
+	if group.Name == "Recycle Bin" {
+		result.skip("%s: recycle bin is not imported", group.Name)
+		return
+	}
+
+	path := append(append([]string{}, parents...), group.Name)
+	importKeePassEntries(result, group.Entries, strings.Join(path, "-"))
+
+	for _, sub := range group.Groups {
+		importKeePassGroup(result, sub, path)
+	}
+}
+
+// importKeePassEntries imports entries into project
+func importKeePassEntries(result *Result, entries []keepassEntry, project string) {
+	for _, entry := range entries {
+		fields := make(map[string]string, len(entry.Strings))
+		for _, s := range entry.Strings {
+			fields[s.Key] = s.Value
+		}
+
+		title := fields["Title"]
+		if title == "" {
+			result.skip("entry in %s: missing title", project)
+			continue
+		}
+
+		added := false
+		for _, s := range entry.Strings {
+			if s.Key == "Title" {
+				continue
+			}
+			field, ok := keepassFields[s.Key]
+			if !ok {
+				field = s.Key
+			}
+			if field == "totp" {
+				added = result.addTOTP(project, title, title, s.Value) || added
+				continue
+			}
+			added = result.add(project, title, field, s.Value) || added
+		}
+
+		if !added {
+			result.skip("%s: no values to import", title)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package importer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+)
+
+func TestImportKeePass(t *testing.T) {
+	result, err := Import(FormatKeePassXML, filepath.Join("testdata", "keepass.xml"), "imported")
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	checkProject(t, result, "imported", map[string]string{"router_password": "admin"})
+	checkProject(t, result, "work", map[string]string{
+		"github_username":     "alice",
+		"github_password":     "hunter2",
+		"github_totp":         testOTPURI,
+		"broken_otp_username": "carol",
+	})
+	checkTypes(t, result, "work", map[string]string{"github_totp": storage.TypeTOTP})
+	checkProject(t, result, "work-cloud", map[string]string{"aws_api_key": "AKIA123"})
+
+	if _, ok := result.Secrets["recycle_bin"]; ok {
+		t.Error("recycle bin entries were imported")
+	}
+
+	// The bad otp value and the recycle bin are reported
+	if len(result.Skipped) != 2 {
+		t.Errorf("skipped = %q, want 2 entries", result.Skipped)
+	}
+}
@@ -0,0 +1,94 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// import1Password reads a 1Password CSV export. Columns are matched by
+// header name; a "vault" column, when present, selects the project.
+func import1Password(path, defaultProject string) (*Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse 1Password CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return newResult(), nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	titleColumn, ok := columns["title"]
+	if !ok {
+		return nil, fmt.Errorf("1Password CSV has no Title column")
+	}
+
+	// Column name -> key suffix, in the order they are imported
+	fields := []struct{ column, field string }{
+		{"username", "username"},
+		{"password", "password"},
+		{"otpauth", "totp"},
+		{"url", "url"},
+		{"website", "url"},
+		{"notes", "notes"},
+	}
+
+	result := newResult()
+	for line, record := range records[1:] {
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+
+		title := get("title")
+		if titleColumn >= len(record) || title == "" {
+			result.skip("row %d: missing title", line+2)
+			continue
+		}
+
+		project := defaultProject
+		if vaultName := get("vault"); vaultName != "" {
+			project = vaultName
+		}
+
+		added := false
+		seen := make(map[string]bool)
+		for _, f := range fields {
+			if seen[f.field] {
+				continue
+			}
+			var stored bool
+			if f.field == "totp" {
+				stored = result.addTOTP(project, title, title, get(f.column))
+			} else {
+				stored = result.add(project, title, f.field, get(f.column))
+			}
+			if stored {
+				seen[f.field] = true
+				added = true
+			}
+		}
+
+		if !added {
+			result.skip("%s: no values to import", title)
+		}
+	}
+
+	return result, nil
+}
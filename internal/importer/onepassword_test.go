@@ -0,0 +1,44 @@
+package importer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+)
+
+func TestImport1Password(t *testing.T) {
+	result, err := Import(FormatOnePasswordCSV, filepath.Join("testdata", "1password.csv"), "imported")
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	checkProject(t, result, "work", map[string]string{
+		"github_username": "alice",
+		"github_password": "hunter2",
+		"github_totp":     testOTPURI,
+		"github_url":      "https://github.com/login",
+	})
+	checkTypes(t, result, "work", map[string]string{"github_totp": storage.TypeTOTP})
+
+	checkProject(t, result, "imported", map[string]string{
+		"seed_only_password":  "pw",
+		"seed_only_totp":      "JBSWY3DPEHPK3PXP",
+		"broken_otp_username": "carol",
+	})
+	checkTypes(t, result, "imported", map[string]string{"seed_only_totp": storage.TypeTOTP})
+
+	// The bad otpauth URI and the row without a title are reported
+	if len(result.Skipped) != 2 {
+		t.Errorf("skipped = %q, want 2 entries", result.Skipped)
+	}
+}
+
+func TestImport1PasswordWithoutTitle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.csv")
+	writeFile(t, path, "Username,Password\nalice,hunter2\n")
+
+	if _, err := Import(FormatOnePasswordCSV, path, "imported"); err == nil {
+		t.Fatal("Import without a Title column succeeded, want an error")
+	}
+}
@@ -0,0 +1,111 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// importPass reads a pass(1) password store. Each .gpg file is decrypted
+// with gpg, so gpg-agent may prompt for the key passphrase. The first
+// directory becomes the project and the rest of the path the key (see
+// addPassEntry for the file contents).
+func importPass(dir, defaultProject string) (*Result, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	if _, err := exec.LookPath("gpg"); err != nil {
+		return nil, fmt.Errorf("gpg is required to import a pass store: %w", err)
+	}
+
+	result := newResult()
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".gpg") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(strings.TrimSuffix(rel, ".gpg")), "/")
+
+		project, item := defaultProject, parts[0]
+		if len(parts) > 1 {
+			project, item = parts[0], strings.Join(parts[1:], "_")
+		}
+
+		plaintext, err := exec.Command("gpg", "--quiet", "--batch", "--decrypt", path).Output()
+		if err != nil {
+			result.skip("%s: gpg decryption failed", rel)
+			return nil
+		}
+		defer func() {
+			for i := range plaintext {
+				plaintext[i] = 0
+			}
+		}()
+
+		addPassEntry(result, project, item, rel, plaintext)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pass store: %w", err)
+	}
+
+	return result, nil
+}
+
+// addPassEntry adds the decrypted contents of a pass entry: the first line
+// is the password, "name: value" lines become extra keys and an otpauth://
+// line becomes a TOTP secret named ITEM_totp. Other lines are skipped.
+// entry identifies the file in skip messages.
+func addPassEntry(result *Result, project, item, entry string, plaintext []byte) {
+	lines := strings.Split(string(bytes.TrimRight(plaintext, "\n")), "\n")
+	if !result.add(project, item, "", lines[0]) {
+		result.skip("%s: empty password", entry)
+	}
+
+	for i, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lineNumber := i + 2
+
+		// pass-otp stores the otpauth URI on a line of its own
+		if strings.HasPrefix(strings.ToLower(line), "otpauth://") {
+			result.addTOTP(project, item, fmt.Sprintf("%s (line %d)", entry, lineNumber), line)
+			continue
+		}
+
+		// Only "name: value" is a field; a bare URL must not be split at its scheme
+		name, value, ok := strings.Cut(line, ": ")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.Contains(name, "/") {
+			result.skip("%s (line %d): not a \"name: value\" field", entry, lineNumber)
+			continue
+		}
+		if !result.add(project, item, name, strings.TrimSpace(value)) {
+			result.skip("%s (line %d): empty field %s", entry, lineNumber, name)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+)
+
+func TestAddPassEntry(t *testing.T) {
+	const otpURI = "otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP&issuer=GitHub"
+	plaintext := strings.Join([]string{
+		"hunter2",
+		"login: alice",
+		"url: https://github.com/login",
+		"https://example.com/bare-url",
+		otpURI,
+		"otpauth://totp/x?secret=not-base32!",
+		"notes:",
+		"",
+	}, "\n")
+
+	result := newResult()
+	addPassEntry(result, "web", "github", "web/github.gpg", []byte(plaintext))
+
+	want := map[string]string{
+		"github":       "hunter2",
+		"github_login": "alice",
+		"github_url":   "https://github.com/login",
+		"github_totp":  otpURI,
+	}
+	got := result.Secrets["web"]
+	if len(got) != len(want) {
+		t.Errorf("got keys %v, want %v", got, want)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %q, want %q", key, got[key], value)
+		}
+	}
+
+	if result.Types["web"]["github_totp"] != storage.TypeTOTP {
+		t.Errorf("github_totp type = %q, want %q", result.Types["web"]["github_totp"], storage.TypeTOTP)
+	}
+	if _, ok := result.Types["web"]["github_url"]; ok {
+		t.Error("github_url has a secret type, want plain")
+	}
+
+	// The bare URL, the bad otpauth line and "notes:" are reported, never mangled
+	if len(result.Skipped) != 3 {
+		t.Errorf("skipped = %q, want 3 entries", result.Skipped)
+	}
+	for _, reason := range result.Skipped {
+		if strings.Contains(reason, "example.com") || strings.Contains(reason, "not-base32") {
+			t.Errorf("skip reason %q reveals the line", reason)
+		}
+	}
+}
+
+func TestAddPassEntryEmptyPassword(t *testing.T) {
+	result := newResult()
+	addPassEntry(result, "web", "empty", "web/empty.gpg", []byte("\nuser: bob\n"))
+
+	if result.Secrets["web"]["empty_user"] != "bob" {
+		t.Errorf("empty_user = %q, want bob", result.Secrets["web"]["empty_user"])
+	}
+	if len(result.Skipped) != 1 || !strings.Contains(result.Skipped[0], "empty password") {
+		t.Errorf("skipped = %q, want the empty password", result.Skipped)
+	}
+}
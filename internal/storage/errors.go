@@ -0,0 +1,22 @@
+package storage
+
+import "errors"
+
+// Errors returned by the vault, wrapped with context where useful; check
+// them with errors.Is. ErrHeaderTampered is defined with the header format.
+var (
+	// ErrLocked is returned by operations that need an unlocked vault
+	ErrLocked = errors.New("vault is locked")
+
+	// ErrNotFound is returned for a missing secret, project, profile or vault file
+	ErrNotFound = errors.New("not found")
+
+	// ErrBadPassword is returned when the master password (or key) is wrong
+	ErrBadPassword = errors.New("invalid master password")
+
+	// ErrCorrupt is returned when the vault file cannot be decoded
+	ErrCorrupt = errors.New("vault is corrupt")
+
+	// ErrExists is returned when a secret, project or vault already exists
+	ErrExists = errors.New("already exists")
+)
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hungnguyen18/uzp-cli/internal/crypto"
+)
+
+// ErrHeaderTampered is returned when the vault key is correct but the
+// header fields bound to the encrypted data have been modified
+var ErrHeaderTampered = errors.New("vault header tampered")
+
+// keyCheckPlaintext is sealed into the header's check block, so a correct
+// key can be told apart from a modified header
+var keyCheckPlaintext = []byte("uzp-key-check")
+
+// headerAAD returns the canonical encoding of the header fields that are
+// authenticated as associated data of the encrypted vault data
+func headerAAD(version int, kdf crypto.KDFParams, salt string) ([]byte, error) {
+	header := struct {
+		Format  string           `json:"format"`
+		Version int              `json:"version"`
+		KDF     crypto.KDFParams `json:"kdf"`
+		Salt    string           `json:"salt"`
+	}{
+		Format:  "uzp-vault",
+		Version: version,
+		KDF:     kdf,
+		Salt:    salt,
+	}
+
+	aad, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode vault header: %w", err)
+	}
+	return aad, nil
+}
+
+// newKeyCheck seals the key check block for the header
+func newKeyCheck(key []byte) (string, error) {
+	check, err := crypto.Encrypt(keyCheckPlaintext, key, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create key check: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(check), nil
+}
+
+// verifyKeyCheck reports whether key opens the header's check block
+func verifyKeyCheck(check string, key []byte) bool {
+	sealed, err := base64.StdEncoding.DecodeString(check)
+	if err != nil {
+		return false
+	}
+
+	plaintext, err := crypto.Decrypt(sealed, key, nil)
+	return err == nil && string(plaintext) == string(keyCheckPlaintext)
+}
+
+// opensWithOtherVersion reports whether data authenticates under the
+// header's KDF and salt with a format version other than the recorded one,
+// which means the version field was modified
+func opensWithOtherVersion(encVault *EncryptedVault, data, key []byte) bool {
+	for version := 3; version <= formatVersion; version++ {
+		if version == encVault.Version {
+			continue
+		}
+
+		aad, err := headerAAD(version, encVault.kdfParams(), encVault.Salt)
+		if err != nil {
+			continue
+		}
+		if plaintext, err := crypto.Decrypt(data, key, aad); err == nil {
+			clear(plaintext)
+			return true
+		}
+	}
+	return false
+}
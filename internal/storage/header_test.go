@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestUnlockWrongPassword(t *testing.T) {
+	v := newTestVault(t)
+
+	err := NewVaultAt(v.Path()).Unlock("wrong password")
+	if !errors.Is(err, ErrBadPassword) {
+		t.Fatalf("Unlock with wrong password = %v, want ErrBadPassword", err)
+	}
+}
+
+func TestUnlockTamperedHeader(t *testing.T) {
+	tests := map[string]func(fields map[string]interface{}){
+		"version changed": func(fields map[string]interface{}) {
+			fields["version"] = float64(formatVersion - 1)
+		},
+		"downgraded to v2 without check block": func(fields map[string]interface{}) {
+			fields["version"] = float64(2)
+			delete(fields, "check")
+		},
+		"check block removed": func(fields map[string]interface{}) {
+			delete(fields, "check")
+		},
+	}
+
+	for name, edit := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := newTestVault(t)
+			if err := v.Add("app", "token", "one"); err != nil {
+				t.Fatal(err)
+			}
+			if err := v.Add("app", "token", "two"); err != nil {
+				t.Fatal(err)
+			}
+			editVaultFile(t, v.Path(), edit)
+
+			// Tampering must be reported even with a good backup present
+			err := NewVaultAt(v.Path()).Unlock(testPassword)
+			if !errors.Is(err, ErrHeaderTampered) {
+				t.Fatalf("Unlock = %v, want ErrHeaderTampered", err)
+			}
+		})
+	}
+}
+
+// corruptData flips a byte in the encrypted data, leaving the header intact
+func corruptData(t *testing.T, path string) {
+	t.Helper()
+	editVaultFile(t, path, func(fields map[string]interface{}) {
+		data, err := base64.StdEncoding.DecodeString(fields["data"].(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data[len(data)/2] ^= 0xff
+		fields["data"] = base64.StdEncoding.EncodeToString(data)
+	})
+}
+
+func TestUnlockCorruptBodyIsNotTampering(t *testing.T) {
+	v := newTestVault(t)
+	if err := v.Add("app", "token", "one"); err != nil {
+		t.Fatal(err)
+	}
+	corruptData(t, v.Path())
+	if err := os.Remove(v.backupPath()); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewVaultAt(v.Path()).Unlock(testPassword)
+	if !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("Unlock = %v, want ErrCorrupt", err)
+	}
+}
+
+func TestUnlockCorruptBodyRecoversFromBackup(t *testing.T) {
+	v := newTestVault(t)
+	if err := v.Add("app", "token", "one"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Add("app", "token", "two"); err != nil {
+		t.Fatal(err)
+	}
+	corruptData(t, v.Path())
+
+	reopened := NewVaultAt(v.Path())
+	if err := reopened.Unlock(testPassword); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if !reopened.Recovered() {
+		t.Error("Recovered() = false, want true")
+	}
+	if got := mustGet(t, reopened, "app", "token"); got != "one" {
+		t.Errorf("recovered value = %q, want the backup's %q", got, "one")
+	}
+}
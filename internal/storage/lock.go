@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	defaultLockTimeout = 10 * time.Second
+	lockRetryInterval  = 50 * time.Millisecond
+)
+
+// errLockBusy is returned by tryLock when another process holds the lock
+var errLockBusy = errors.New("lock is held by another process")
+
+// fileLock is an advisory inter-process lock backed by a lock file
+type fileLock struct {
+	file *os.File
+}
+
+// acquireLock takes an exclusive lock on path, waiting up to timeout
+func acquireLock(path string, timeout time.Duration) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := tryLock(f)
+		if err == nil {
+			return &fileLock{file: f}, nil
+		}
+
+		if !errors.Is(err, errLockBusy) {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock vault: %w", err)
+		}
+
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("timed out after %s waiting for another uzp process to release %s", timeout, path)
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// release unlocks and closes the lock file
+func (l *fileLock) release() error {
+	unlockErr := unlock(l.file)
+	closeErr := l.file.Close()
+	if unlockErr != nil {
+		return fmt.Errorf("failed to unlock vault: %w", unlockErr)
+	}
+	return closeErr
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentMutateKeepsAllWrites(t *testing.T) {
+	v := newTestVault(t)
+
+	// Separate instances stand in for separate uzp processes; each one
+	// unlocked before the others wrote, so only reload under the lock
+	// keeps their writes from overwriting each other
+	const writers, writes = 4, 5
+	vaults := make([]*Vault, writers)
+	for i := range vaults {
+		vaults[i] = NewVaultAt(v.Path())
+		if err := vaults[i].Unlock(testPassword); err != nil {
+			t.Fatalf("Unlock: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*writes)
+	for i, w := range vaults {
+		wg.Add(1)
+		go func(i int, w *Vault) {
+			defer wg.Done()
+			for j := 0; j < writes; j++ {
+				if err := w.Add("app", fmt.Sprintf("key_%d_%d", i, j), "value"); err != nil {
+					errs <- err
+				}
+			}
+		}(i, w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Add: %v", err)
+	}
+
+	reopened := NewVaultAt(v.Path())
+	if err := reopened.Unlock(testPassword); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	secrets, err := reopened.GetProjectSecrets("app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != writers*writes {
+		t.Errorf("got %d secrets, want %d", len(secrets), writers*writes)
+	}
+}
+
+func TestAcquireLockTimesOut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uzp.vault.lock")
+
+	held, err := acquireLock(path, time.Second)
+	if err != nil {
+		t.Fatalf("acquireLock: %v", err)
+	}
+
+	if _, err := acquireLock(path, 100*time.Millisecond); err == nil {
+		t.Fatal("second acquireLock succeeded while the lock was held")
+	}
+
+	if err := held.release(); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	again, err := acquireLock(path, time.Second)
+	if err != nil {
+		t.Fatalf("acquireLock after release: %v", err)
+	}
+	again.release()
+}
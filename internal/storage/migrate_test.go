@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hungnguyen18/uzp-cli/internal/crypto"
+)
+
+// writeLegacyVault writes a vault file as written by format version 1
+// (unsalted password hash, no version or KDF in the header, string values
+// and no associated data) or version 2 (no hash)
+func writeLegacyVault(t *testing.T, path string, version int, projects map[string]map[string]string) {
+	t.Helper()
+
+	salt, err := crypto.GenerateSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.DeriveKey(testPassword, salt, crypto.LegacyKDFParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedSalt := base64.StdEncoding.EncodeToString(salt)
+
+	sum := sha256.Sum256([]byte(testPassword))
+	payload := map[string]interface{}{
+		"version":  version,
+		"salt":     encodedSalt,
+		"projects": projects,
+	}
+	header := map[string]interface{}{
+		"salt": encodedSalt,
+	}
+	if version == 1 {
+		payload["hash"] = hex.EncodeToString(sum[:])
+		header["hash"] = hex.EncodeToString(sum[:])
+	} else {
+		header["version"] = version
+	}
+
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := crypto.Encrypt(plaintext, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header["data"] = base64.StdEncoding.EncodeToString(data)
+
+	raw, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrateLegacyVault(t *testing.T) {
+	for _, version := range []int{1, 2} {
+		t.Run(map[int]string{1: "v1", 2: "v2"}[version], func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "uzp.vault")
+			writeLegacyVault(t, path, version, map[string]map[string]string{
+				"app": {"token": "legacy-value"},
+			})
+			legacy, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v := NewVaultAt(path)
+			if err := v.Unlock(testPassword); err != nil {
+				t.Fatalf("Unlock: %v", err)
+			}
+			if got := mustGet(t, v, "app", "token"); got != "legacy-value" {
+				t.Errorf("value = %q, want %q", got, "legacy-value")
+			}
+			if limit, _ := v.HistoryLimit(); limit != DefaultHistoryLimit {
+				t.Errorf("history limit = %d, want %d", limit, DefaultHistoryLimit)
+			}
+
+			// The file was rewritten in the current format
+			var header map[string]interface{}
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(raw, &header); err != nil {
+				t.Fatal(err)
+			}
+			if header["version"] != float64(formatVersion) {
+				t.Errorf("header version = %v, want %d", header["version"], formatVersion)
+			}
+			if _, ok := header["hash"]; ok {
+				t.Error("migrated header still has the password hash")
+			}
+			if header["check"] == nil || header["kdf"] == nil {
+				t.Error("migrated header has no key check or KDF")
+			}
+
+			// A version 1 backup would keep the password hash around; later
+			// formats stay as the previous generation
+			backup, err := os.ReadFile(v.backupPath())
+			if version == 1 {
+				if !os.IsNotExist(err) {
+					t.Errorf("backup of the version 1 file was kept: %v", err)
+				}
+			} else if err != nil || !bytes.Equal(backup, legacy) {
+				t.Errorf("backup is not the version %d file: %v", version, err)
+			}
+
+			reopened := NewVaultAt(path)
+			if err := reopened.Unlock(testPassword); err != nil {
+				t.Fatalf("Unlock after migration: %v", err)
+			}
+			secret, err := reopened.GetSecret("app", "token")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if secret.Value != "legacy-value" || !secret.CreatedAt.IsZero() {
+				t.Errorf("migrated secret = %+v, want the legacy value with no creation time", secret)
+			}
+		})
+	}
+}
+
+func TestMigrateLegacyVaultWrongPassword(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uzp.vault")
+	writeLegacyVault(t, path, 1, map[string]map[string]string{"app": {"token": "x"}})
+
+	if err := NewVaultAt(path).Unlock("wrong password"); !errors.Is(err, ErrBadPassword) {
+		t.Fatalf("Unlock with wrong password = %v, want ErrBadPassword", err)
+	}
+}
@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile backed by the original uzp.vault file
+const DefaultProfile = "default"
+
+const (
+	dataDirName     = ".uzp"
+	vaultFileName   = "uzp.vault"
+	configFileName  = "config.json"
+	profilesDirName = "profiles"
+	profileExt      = ".vault"
+)
+
+// Config holds settings stored in the uzp data directory
+type Config struct {
+	DefaultProfile string `json:"default_profile,omitempty"`
+}
+
+// DataDir returns the directory holding vaults and the config file.
+// An existing ~/.uzp always wins so upgrades keep working; otherwise
+// $XDG_DATA_HOME/uzp is used when XDG_DATA_HOME is set.
+func DataDir() (string, error) {
+	homeDir, homeErr := os.UserHomeDir()
+	if homeErr == nil {
+		legacyDir := filepath.Join(homeDir, dataDirName)
+		if info, err := os.Stat(legacyDir); err == nil && info.IsDir() {
+			return legacyDir, nil
+		}
+	}
+
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "uzp"), nil
+	}
+
+	if homeErr != nil {
+		return "", fmt.Errorf("cannot determine vault location (set --vault or UZP_VAULT): %w", homeErr)
+	}
+
+	return filepath.Join(homeDir, dataDirName), nil
+}
+
+// ValidateProfileName checks that name is safe to use as a file name
+func ValidateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name cannot be empty")
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == '_') {
+			return fmt.Errorf("invalid profile name %q: use letters, digits, '-' or '_'", name)
+		}
+	}
+	return nil
+}
+
+// ProfileVaultPath returns the vault file for the named profile
+func ProfileVaultPath(name string) (string, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+
+	if name == DefaultProfile {
+		return filepath.Join(dir, vaultFileName), nil
+	}
+	return filepath.Join(dir, profilesDirName, name+profileExt), nil
+}
+
+// ListProfiles returns the names of all profiles that have a vault file
+func ListProfiles() ([]string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []string{}
+	if _, err := os.Stat(filepath.Join(dir, vaultFileName)); err == nil {
+		profiles = append(profiles, DefaultProfile)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, profilesDirName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read profiles directory: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, profileExt) {
+			continue
+		}
+		profiles = append(profiles, strings.TrimSuffix(name, profileExt))
+	}
+
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// ProfileExists reports whether the named profile has a vault file
+func ProfileExists(name string) (bool, error) {
+	path, err := ProfileVaultPath(name)
+	if err != nil {
+		return false, err
+	}
+	return NewVaultAt(path).Exists(), nil
+}
+
+// CurrentProfile returns the default profile recorded in the config file
+func CurrentProfile() (string, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	if config.DefaultProfile == "" {
+		return DefaultProfile, nil
+	}
+	return config.DefaultProfile, nil
+}
+
+// SetCurrentProfile records name as the default profile
+func SetCurrentProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	config.DefaultProfile = name
+	return SaveConfig(config)
+}
+
+// DeleteProfile removes the vault file of the named profile and its backup.
+// If it was the current default, the default profile is selected again.
+func DeleteProfile(name string) error {
+	path, err := ProfileVaultPath(name)
+	if err != nil {
+		return err
+	}
+
+	v := NewVaultAt(path)
+	if !v.Exists() {
+		return fmt.Errorf("profile %w: %s", ErrNotFound, name)
+	}
+
+	err = v.withLock(func() error {
+		for _, p := range []string{v.path, v.backupPath()} {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to delete %s: %w", p, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_ = os.Remove(v.lockPath())
+
+	current, err := CurrentProfile()
+	if err != nil {
+		return err
+	}
+	if current == name {
+		return SetCurrentProfile(DefaultProfile)
+	}
+	return nil
+}
+
+// LoadConfig reads the config file, returning an empty config if it is missing
+func LoadConfig() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// SaveConfig writes the config file
+func SaveConfig(config *Config) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	return writeFileAtomic(path, data, 0600)
+}
+
+// configPath returns the location of the config file
+func configPath() (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFileName), nil
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Secret types
+const (
+	TypePlain = ""     // Any value
+	TypeTOTP  = "totp" // otpauth://totp/ URI or base32 seed, see uzp otp
+)
+
+// Secret is a stored value with its metadata
+type Secret struct {
+	Type      string     `json:"type,omitempty"`
+	Value     string     `json:"value"`
+	CreatedAt time.Time  `json:"created_at"` // Zero for secrets migrated from format version 3 or older
+	UpdatedAt time.Time  `json:"updated_at"` // Last change of the value
+	Notes     string     `json:"notes,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	History   []Revision `json:"history,omitempty"` // Previous values, newest first
+
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	RotateEvery string     `json:"rotate_every,omitempty"` // Interval such as "90d", see ParseInterval
+}
+
+// Revision is a previous value of a secret
+type Revision struct {
+	Value      string    `json:"value"`
+	SetAt      time.Time `json:"set_at"` // Zero if unknown
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// UnmarshalJSON accepts both a secret record and the bare string values
+// stored by format version 3 and older
+func (s *Secret) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*s = Secret{Value: value}
+		return nil
+	}
+
+	type record Secret // Avoids recursing into this method
+	var r record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	*s = Secret(r)
+	return nil
+}
+
+// clone returns a deep copy of the secret
+func (s Secret) clone() Secret {
+	s.Tags = append([]string(nil), s.Tags...)
+	s.History = append([]Revision(nil), s.History...)
+	if s.ExpiresAt != nil {
+		expires := *s.ExpiresAt
+		s.ExpiresAt = &expires
+	}
+	return s
+}
+
+// DueAt returns when the secret must be rotated: the earlier of its expiry
+// date and its last update plus the rotation interval. ok is false when
+// neither is set.
+func (s Secret) DueAt() (due time.Time, ok bool) {
+	if s.ExpiresAt != nil {
+		due, ok = *s.ExpiresAt, true
+	}
+
+	if s.RotateEvery != "" {
+		interval, err := ParseInterval(s.RotateEvery)
+		if err == nil {
+			last := s.UpdatedAt
+			if last.IsZero() {
+				last = s.CreatedAt
+			}
+			if !last.IsZero() {
+				rotateAt := last.Add(interval)
+				if !ok || rotateAt.Before(due) {
+					due, ok = rotateAt, true
+				}
+			}
+		}
+	}
+
+	return due, ok
+}
+
+// ParseInterval parses a rotation interval: a number of days ("90d") or
+// weeks ("2w"), or a Go duration such as "36h"
+func ParseInterval(s string) (time.Duration, error) {
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+
+	var interval time.Duration
+	if unit != 0 {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %q: use e.g. 90d, 2w or 36h", s)
+		}
+		interval = time.Duration(n) * unit
+	} else {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %q: use e.g. 90d, 2w or 36h", s)
+		}
+		interval = d
+	}
+
+	if interval <= 0 {
+		return 0, fmt.Errorf("invalid interval %q: must be positive", s)
+	}
+	return interval, nil
+}
+
+// recordRevision adds the value of previous to the history, replaced at
+// the given time, keeping at most limit revisions
+func (s *Secret) recordRevision(previous Secret, replacedAt time.Time, limit int) {
+	revision := Revision{
+		Value:      previous.Value,
+		SetAt:      previous.UpdatedAt,
+		ReplacedAt: replacedAt,
+	}
+	s.History = append([]Revision{revision}, s.History...)
+	s.trimHistory(limit)
+}
+
+// trimHistory drops the oldest revisions beyond limit
+func (s *Secret) trimHistory(limit int) {
+	if len(s.History) > limit {
+		s.History = s.History[:limit]
+	}
+	if len(s.History) == 0 {
+		s.History = nil
+	}
+}
+
+// now returns the current time as stored in secret timestamps
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTrimHistory(t *testing.T) {
+	s := Secret{History: []Revision{{Value: "c"}, {Value: "b"}, {Value: "a"}}}
+
+	s.trimHistory(2)
+	if len(s.History) != 2 || s.History[0].Value != "c" || s.History[1].Value != "b" {
+		t.Errorf("trimHistory(2) = %+v, want the two newest revisions", s.History)
+	}
+
+	s.trimHistory(0)
+	if s.History != nil {
+		t.Errorf("trimHistory(0) = %+v, want nil", s.History)
+	}
+}
+
+func TestRecordRevision(t *testing.T) {
+	setAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	replacedAt := setAt.Add(time.Hour)
+
+	var s Secret
+	for _, value := range []string{"one", "two", "three"} {
+		s.recordRevision(Secret{Value: value, UpdatedAt: setAt}, replacedAt, 2)
+	}
+
+	if len(s.History) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(s.History))
+	}
+	if s.History[0].Value != "three" || s.History[1].Value != "two" {
+		t.Errorf("history = %+v, want newest first", s.History)
+	}
+	if !s.History[0].SetAt.Equal(setAt) || !s.History[0].ReplacedAt.Equal(replacedAt) {
+		t.Errorf("revision times = %+v", s.History[0])
+	}
+}
+
+func TestPutRecordsHistory(t *testing.T) {
+	v := newTestVault(t)
+	for _, value := range []string{"one", "two", "two", "three"} {
+		if err := v.Add("app", "token", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	secret, err := v.GetSecret("app", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Saving an unchanged value adds no revision
+	if len(secret.History) != 2 || secret.History[0].Value != "two" || secret.History[1].Value != "one" {
+		t.Errorf("history = %+v, want two, one", secret.History)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	v := newTestVault(t)
+	for _, value := range []string{"one", "two", "three"} {
+		if err := v.Add("app", "token", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := v.Rollback("app", "token", 2); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if got := mustGet(t, v, "app", "token"); got != "one" {
+		t.Errorf("value after rollback = %q, want %q", got, "one")
+	}
+
+	// The rolled-back value is kept, so the rollback can be undone
+	if err := v.Rollback("app", "token", 1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if got := mustGet(t, v, "app", "token"); got != "three" {
+		t.Errorf("value after undoing rollback = %q, want %q", got, "three")
+	}
+
+	for _, n := range []int{0, -1, 99} {
+		if err := v.Rollback("app", "token", n); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Rollback(%d) = %v, want ErrNotFound", n, err)
+		}
+	}
+	if err := v.Rollback("app", "missing", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Rollback of a missing secret = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSetHistoryLimitTrimsHistory(t *testing.T) {
+	v := newTestVault(t)
+	for _, value := range []string{"one", "two", "three", "four"} {
+		if err := v.Add("app", "token", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := v.SetHistoryLimit(1); err != nil {
+		t.Fatalf("SetHistoryLimit: %v", err)
+	}
+	if err := v.SetHistoryLimit(-1); err == nil {
+		t.Error("SetHistoryLimit(-1) succeeded")
+	}
+
+	reopened := NewVaultAt(v.Path())
+	if err := reopened.Unlock(testPassword); err != nil {
+		t.Fatal(err)
+	}
+	secret, err := reopened.GetSecret("app", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret.History) != 1 || secret.History[0].Value != "three" {
+		t.Errorf("history = %+v, want only three", secret.History)
+	}
+
+	// New values respect the limit too
+	if err := reopened.Add("app", "token", "five"); err != nil {
+		t.Fatal(err)
+	}
+	secret, _ = reopened.GetSecret("app", "token")
+	if len(secret.History) != 1 || secret.History[0].Value != "four" {
+		t.Errorf("history = %+v, want only four", secret.History)
+	}
+}
@@ -1,46 +1,89 @@
 package storage
 
 import (
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/hungnguyen18/uzp-cli/internal/crypto"
 )
 
+// formatVersion is the current vault file format.
+//
+//	1: password verified by an unsalted SHA-256 "hash" field
+//	2: no hash field; the password is verified by decrypting the data
+//	3: header (version, KDF, salt) authenticated as AEAD associated data,
+//	   plus a key check block to tell a wrong password from a tampered header
+//	4: secrets are records with timestamps and metadata instead of strings
+//	5: per-secret value history and a vault-wide history limit
+//	6: optional expiry date and rotation interval per secret
+//	7: secret type (plain or totp)
+const formatVersion = 7
+
+// DefaultHistoryLimit is the number of previous values kept per secret
+const DefaultHistoryLimit = 10
+
 type VaultData struct {
-	Version  int                          `json:"version"`
-	Salt     string                       `json:"salt"`
-	Hash     string                       `json:"hash"` // Password hash for verification
-	Projects map[string]map[string]string `json:"projects"`
+	Version      int                          `json:"version"`
+	Salt         string                       `json:"salt"`
+	KDF          crypto.KDFParams             `json:"kdf"`
+	HistoryLimit int                          `json:"history_limit"` // 0 keeps no history
+	Projects     map[string]map[string]Secret `json:"projects"`
 }
 
 type EncryptedVault struct {
-	Salt string `json:"salt"`
-	Hash string `json:"hash"`
-	Data string `json:"data"` // Base64 encoded encrypted data
+	Version int               `json:"version,omitempty"` // Missing in version 1 files
+	KDF     *crypto.KDFParams `json:"kdf,omitempty"`     // Missing before KDF parameters were stored
+	Salt    string            `json:"salt"`
+	Check   string            `json:"check,omitempty"` // Key check block, from version 3
+	Data    string            `json:"data"`            // Base64 encoded encrypted data
 }
 
 type Vault struct {
-	path     string
-	data     *VaultData
-	key      []byte
-	unlocked bool
+	path        string
+	data        *VaultData
+	key         []byte
+	unlocked    bool
+	recovered   bool
+	damagedPath string
 }
 
-// NewVault creates a new vault instance
-func NewVault() *Vault {
-	homeDir, _ := os.UserHomeDir()
-	vaultDir := filepath.Join(homeDir, ".uzp")
-	vaultPath := filepath.Join(vaultDir, "uzp.vault")
+// NewVault creates a vault instance for the named profile.
+// An empty profile selects the current default from the config file.
+func NewVault(profile string) (*Vault, error) {
+	if profile == "" {
+		current, err := CurrentProfile()
+		if err != nil {
+			return nil, err
+		}
+		profile = current
+	}
+
+	path, err := ProfileVaultPath(profile)
+	if err != nil {
+		return nil, err
+	}
+	return NewVaultAt(path), nil
+}
 
+// NewVaultAt creates a vault instance backed by the file at path
+func NewVaultAt(path string) *Vault {
 	return &Vault{
-		path: vaultPath,
+		path: path,
 	}
 }
 
+// Path returns the location of the vault file
+func (v *Vault) Path() string {
+	return v.path
+}
+
 // Initialize creates a new vault with the given master password
 func (v *Vault) Initialize(masterPassword string) error {
 	// Create vault directory if not exists
@@ -49,11 +92,6 @@ func (v *Vault) Initialize(masterPassword string) error {
 		return fmt.Errorf("failed to create vault directory: %w", err)
 	}
 
-	// Check if vault already exists
-	if _, err := os.Stat(v.path); err == nil {
-		return fmt.Errorf("vault already exists at %s", v.path)
-	}
-
 	// Generate salt
 	salt, err := crypto.GenerateSalt()
 	if err != nil {
@@ -61,73 +99,203 @@ func (v *Vault) Initialize(masterPassword string) error {
 	}
 
 	// Derive key from password
-	key, err := crypto.DeriveKey(masterPassword, salt)
+	kdf := crypto.DefaultKDFParams()
+	key, err := crypto.DeriveKey(masterPassword, salt, kdf)
 	if err != nil {
 		return err
 	}
 
-	// Create initial vault data
-	v.data = &VaultData{
-		Version:  1,
-		Salt:     base64.StdEncoding.EncodeToString(salt),
-		Hash:     crypto.HashPassword(masterPassword),
-		Projects: make(map[string]map[string]string),
+	return v.withLock(func() error {
+		// Check if vault already exists
+		if _, err := os.Stat(v.path); err == nil {
+			return fmt.Errorf("vault %w at %s", ErrExists, v.path)
+		}
+
+		// Create initial vault data
+		v.data = &VaultData{
+			Version:      formatVersion,
+			Salt:         base64.StdEncoding.EncodeToString(salt),
+			KDF:          kdf,
+			HistoryLimit: DefaultHistoryLimit,
+			Projects:     make(map[string]map[string]Secret),
+		}
+
+		v.key = key
+		v.unlocked = true
+
+		// Save vault
+		return v.save()
+	})
+}
+
+// Unlock unlocks the vault with the master password.
+// If the primary vault file is missing, unreadable or corrupt, the previous
+// generation in the backup file is used and restored as the primary; a
+// damaged primary is moved aside rather than overwritten.
+// Vaults in an older format are upgraded to the current one.
+func (v *Vault) Unlock(masterPassword string) error {
+	vaultData, key, err := v.unlockFile(v.path, masterPassword)
+	if err != nil {
+		// A wrong password or a modified header must never roll the vault
+		// back to the previous generation
+		if !recoverable(err) {
+			return err
+		}
+
+		backupData, backupKey, backupErr := v.unlockFile(v.backupPath(), masterPassword)
+		if backupErr != nil {
+			return err
+		}
+
+		// Restore the backup as the primary vault file
+		raw, readErr := os.ReadFile(v.backupPath())
+		if readErr != nil {
+			return err
+		}
+		restoreErr := v.withLock(func() error {
+			return v.restoreBackup(raw)
+		})
+		if restoreErr != nil {
+			return fmt.Errorf("failed to restore vault from backup: %w", restoreErr)
+		}
+
+		vaultData, key = backupData, backupKey
+		v.recovered = true
 	}
 
+	v.data = vaultData
 	v.key = key
 	v.unlocked = true
 
-	// Save vault
-	return v.save()
+	if v.data.Version < formatVersion {
+		if err := v.migrate(); err != nil {
+			v.Lock()
+			return fmt.Errorf("failed to upgrade vault format: %w", err)
+		}
+	}
+
+	return nil
 }
 
-// Unlock unlocks the vault with the master password
-func (v *Vault) Unlock(masterPassword string) error {
-	// Load encrypted vault
-	encVault, err := v.loadEncrypted()
+// UnlockWithKey unlocks the vault with an already derived key, such as one
+// held by the unlock agent
+func (v *Vault) UnlockWithKey(key []byte) error {
+	encVault, err := loadEncrypted(v.path)
 	if err != nil {
 		return err
 	}
 
-	// Verify password hash
-	if crypto.HashPassword(masterPassword) != encVault.Hash {
-		return fmt.Errorf("invalid master password")
+	vaultData, err := decryptVault(encVault, key)
+	if err != nil {
+		return err
 	}
 
-	// Decode salt
-	salt, err := base64.StdEncoding.DecodeString(encVault.Salt)
-	if err != nil {
-		return fmt.Errorf("failed to decode salt: %w", err)
+	v.data = vaultData
+	v.key = append([]byte(nil), key...)
+	v.unlocked = true
+
+	if v.data.Version < formatVersion {
+		if err := v.migrate(); err != nil {
+			v.Lock()
+			return fmt.Errorf("failed to upgrade vault format: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Key returns a copy of the derived vault key, for handing to the unlock
+// agent. The caller is responsible for clearing it.
+func (v *Vault) Key() ([]byte, error) {
+	if !v.unlocked {
+		return nil, ErrLocked
+	}
+	return append([]byte(nil), v.key...), nil
+}
+
+// migrate rewrites an unlocked vault in the current format
+func (v *Vault) migrate() error {
+	return v.withLock(func() error {
+		if err := v.reload(); err != nil {
+			return err
+		}
+
+		from := v.data.Version
+		v.data.Version = formatVersion
+		if err := v.save(); err != nil {
+			return err
+		}
+
+		// A version 1 backup holds the unsalted password hash; later
+		// formats are kept as the previous generation
+		if from > 1 {
+			return nil
+		}
+		if err := os.Remove(v.backupPath()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove vault backup: %w", err)
+		}
+		return nil
+	})
+}
+
+// recoverable reports whether err means the primary vault file is missing,
+// unreadable or damaged, so its backup may be used instead
+func recoverable(err error) bool {
+	var pathErr *fs.PathError
+	return errors.Is(err, ErrCorrupt) || errors.Is(err, ErrNotFound) || errors.As(err, &pathErr)
+}
+
+// restoreBackup writes raw, the backup file, as the primary vault file.
+// A damaged primary is kept next to it for inspection. The caller must
+// hold the vault lock.
+func (v *Vault) restoreBackup(raw []byte) error {
+	damagedPath := fmt.Sprintf("%s.corrupt-%s", v.path, time.Now().UTC().Format("20060102T150405Z"))
+	if err := os.Rename(v.path, damagedPath); err == nil {
+		v.damagedPath = damagedPath
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to move damaged vault aside: %w", err)
 	}
 
-	// Derive key
-	key, err := crypto.DeriveKey(masterPassword, salt)
+	return writeFileAtomic(v.path, raw, 0600)
+}
+
+// unlockFile decrypts the vault file at path with the master password
+func (v *Vault) unlockFile(path, masterPassword string) (*VaultData, []byte, error) {
+	// Load encrypted vault
+	encVault, err := loadEncrypted(path)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	// Decrypt data
-	encryptedData, err := base64.StdEncoding.DecodeString(encVault.Data)
+	// Decode salt
+	salt, err := base64.StdEncoding.DecodeString(encVault.Salt)
 	if err != nil {
-		return fmt.Errorf("failed to decode encrypted data: %w", err)
+		return nil, nil, fmt.Errorf("%w: failed to decode salt: %w", ErrCorrupt, err)
 	}
 
-	decryptedData, err := crypto.Decrypt(encryptedData, key)
+	// Derive key with the parameters recorded in the header
+	key, err := crypto.DeriveKey(masterPassword, salt, encVault.kdfParams())
 	if err != nil {
-		return fmt.Errorf("failed to decrypt vault: %w", err)
+		return nil, nil, err
 	}
 
-	// Unmarshal vault data
-	var vaultData VaultData
-	if err := json.Unmarshal(decryptedData, &vaultData); err != nil {
-		return fmt.Errorf("failed to unmarshal vault data: %w", err)
+	vaultData, err := decryptVault(encVault, key)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	v.data = &vaultData
-	v.key = key
-	v.unlocked = true
+	return vaultData, key, nil
+}
 
-	return nil
+// Recovered reports whether the last Unlock fell back to the backup file
+func (v *Vault) Recovered() bool {
+	return v.recovered
+}
+
+// DamagedPath returns where the last Unlock moved a damaged vault file
+// before restoring the backup, or "" if the vault file was missing
+func (v *Vault) DamagedPath() string {
+	return v.damagedPath
 }
 
 // Lock locks the vault
@@ -148,39 +316,117 @@ func (v *Vault) IsUnlocked() bool {
 	return v.unlocked
 }
 
-// Add adds a secret to the vault
+// Add adds a secret to the vault, or changes the value of an existing one
+// while keeping its metadata
 func (v *Vault) Add(project, key, value string) error {
+	return v.Put(project, key, func(secret *Secret) error {
+		secret.Value = value
+		return nil
+	})
+}
+
+// Put creates or modifies a secret with fn in a single save. New secrets
+// get a creation time; when the value changes the update time is set and
+// the replaced value is kept in the secret's history.
+func (v *Vault) Put(project, key string, fn func(secret *Secret) error) error {
 	if !v.unlocked {
-		return fmt.Errorf("vault is locked")
+		return ErrLocked
 	}
 
-	if v.data.Projects[project] == nil {
-		v.data.Projects[project] = make(map[string]string)
+	return v.mutate(func(projects map[string]map[string]Secret) error {
+		if projects[project] == nil {
+			projects[project] = make(map[string]Secret)
+		}
+
+		secret, exists := projects[project][key]
+		previous := secret
+		if !exists {
+			secret.CreatedAt = now()
+		}
+		if err := fn(&secret); err != nil {
+			return err
+		}
+		if secret.Value == "" {
+			return fmt.Errorf("value cannot be empty")
+		}
+
+		if !exists || secret.Value != previous.Value {
+			secret.UpdatedAt = now()
+		}
+		if exists && secret.Value != previous.Value {
+			secret.recordRevision(previous, secret.UpdatedAt, v.data.HistoryLimit)
+		}
+		projects[project][key] = secret
+		return nil
+	})
+}
+
+// Import adds or overwrites many secret values, across any number of
+// projects, in a single save. Unchanged values keep their timestamps.
+// types optionally sets the secret type of imported keys (see TypeTOTP).
+func (v *Vault) Import(secrets map[string]map[string]string, types map[string]map[string]string) error {
+	if !v.unlocked {
+		return ErrLocked
 	}
 
-	v.data.Projects[project][key] = value
-	return v.save()
+	return v.mutate(func(projects map[string]map[string]Secret) error {
+		timestamp := now()
+		for project, values := range secrets {
+			if projects[project] == nil {
+				projects[project] = make(map[string]Secret)
+			}
+			for key, value := range values {
+				secret, exists := projects[project][key]
+				secretType, typed := types[project][key]
+				if typed {
+					secret.Type = secretType
+				}
+				if exists && secret.Value == value {
+					projects[project][key] = secret
+					continue
+				}
+				if exists {
+					secret.recordRevision(secret, timestamp, v.data.HistoryLimit)
+				} else {
+					secret.CreatedAt = timestamp
+				}
+				secret.Value = value
+				secret.UpdatedAt = timestamp
+				projects[project][key] = secret
+			}
+		}
+		return nil
+	})
 }
 
-// Get retrieves a secret from the vault
+// Get retrieves a secret value from the vault
 func (v *Vault) Get(project, key string) (string, error) {
+	secret, err := v.GetSecret(project, key)
+	if err != nil {
+		return "", err
+	}
+	return secret.Value, nil
+}
+
+// GetSecret retrieves a secret with its metadata
+func (v *Vault) GetSecret(project, key string) (Secret, error) {
 	if !v.unlocked {
-		return "", fmt.Errorf("vault is locked")
+		return Secret{}, ErrLocked
 	}
 
 	if proj, ok := v.data.Projects[project]; ok {
-		if val, ok := proj[key]; ok {
-			return val, nil
+		if secret, ok := proj[key]; ok {
+			return secret.clone(), nil
 		}
 	}
 
-	return "", fmt.Errorf("secret not found: %s/%s", project, key)
+	return Secret{}, fmt.Errorf("secret %w: %s/%s", ErrNotFound, project, key)
 }
 
 // List returns all projects and keys
 func (v *Vault) List() (map[string][]string, error) {
 	if !v.unlocked {
-		return nil, fmt.Errorf("vault is locked")
+		return nil, ErrLocked
 	}
 
 	result := make(map[string][]string)
@@ -198,7 +444,7 @@ func (v *Vault) List() (map[string][]string, error) {
 // Search searches for keys or projects containing the keyword
 func (v *Vault) Search(keyword string) (map[string][]string, error) {
 	if !v.unlocked {
-		return nil, fmt.Errorf("vault is locked")
+		return nil, ErrLocked
 	}
 
 	result := make(map[string][]string)
@@ -220,35 +466,457 @@ func (v *Vault) Search(keyword string) (map[string][]string, error) {
 // GetProjectSecrets returns all secrets for a project
 func (v *Vault) GetProjectSecrets(project string) (map[string]string, error) {
 	if !v.unlocked {
-		return nil, fmt.Errorf("vault is locked")
+		return nil, ErrLocked
 	}
 
 	if proj, ok := v.data.Projects[project]; ok {
 		// Return a copy to prevent external modification
 		result := make(map[string]string)
-		for k, v := range proj {
-			result[k] = v
+		for k, secret := range proj {
+			result[k] = secret.Value
 		}
 		return result, nil
 	}
 
-	return nil, fmt.Errorf("project not found: %s", project)
+	return nil, fmt.Errorf("project %w: %s", ErrNotFound, project)
+}
+
+// Rollback restores the value of revision n (1 is the most recent previous
+// value). The current value is kept in the history, so a rollback can
+// itself be rolled back.
+func (v *Vault) Rollback(project, key string, n int) error {
+	if !v.unlocked {
+		return ErrLocked
+	}
+
+	if _, err := v.GetSecret(project, key); err != nil {
+		return err
+	}
+
+	return v.Put(project, key, func(secret *Secret) error {
+		if n < 1 || n > len(secret.History) {
+			return fmt.Errorf("revision %d of %s/%s %w", n, project, key, ErrNotFound)
+		}
+		secret.Value = secret.History[n-1].Value
+		return nil
+	})
+}
+
+// HistoryLimit returns the number of previous values kept per secret
+func (v *Vault) HistoryLimit() (int, error) {
+	if !v.unlocked {
+		return 0, ErrLocked
+	}
+	return v.data.HistoryLimit, nil
+}
+
+// SetHistoryLimit changes the number of previous values kept per secret
+// and drops revisions beyond it
+func (v *Vault) SetHistoryLimit(limit int) error {
+	if !v.unlocked {
+		return ErrLocked
+	}
+	if limit < 0 {
+		return fmt.Errorf("history limit cannot be negative")
+	}
+
+	return v.withLock(func() error {
+		if err := v.reload(); err != nil {
+			return err
+		}
+
+		projects := cloneProjects(v.data.Projects)
+		for _, secrets := range projects {
+			for key, secret := range secrets {
+				secret.trimHistory(limit)
+				secrets[key] = secret
+			}
+		}
+
+		previousProjects, previousLimit := v.data.Projects, v.data.HistoryLimit
+		v.data.Projects, v.data.HistoryLimit = projects, limit
+		if err := v.save(); err != nil {
+			v.data.Projects, v.data.HistoryLimit = previousProjects, previousLimit
+			return err
+		}
+		return nil
+	})
+}
+
+// Remove deletes a single secret from the vault
+func (v *Vault) Remove(project, key string) error {
+	if !v.unlocked {
+		return ErrLocked
+	}
+
+	return v.mutate(func(projects map[string]map[string]Secret) error {
+		if _, ok := projects[project][key]; !ok {
+			return fmt.Errorf("secret %w: %s/%s", ErrNotFound, project, key)
+		}
+
+		delete(projects[project], key)
+		return nil
+	})
+}
+
+// RemoveProject deletes a project and all of its secrets
+func (v *Vault) RemoveProject(project string) error {
+	if !v.unlocked {
+		return ErrLocked
+	}
+
+	return v.mutate(func(projects map[string]map[string]Secret) error {
+		if _, ok := projects[project]; !ok {
+			return fmt.Errorf("project %w: %s", ErrNotFound, project)
+		}
+
+		delete(projects, project)
+		return nil
+	})
+}
+
+// Move renames a secret, optionally into another project
+func (v *Vault) Move(srcProject, srcKey, dstProject, dstKey string, overwrite bool) error {
+	return v.transfer(srcProject, srcKey, dstProject, dstKey, overwrite, true)
+}
+
+// Copy duplicates a secret under a new project and/or key
+func (v *Vault) Copy(srcProject, srcKey, dstProject, dstKey string, overwrite bool) error {
+	return v.transfer(srcProject, srcKey, dstProject, dstKey, overwrite, false)
+}
+
+// MoveProject renames a project, merging into the destination when overwrite is set
+func (v *Vault) MoveProject(src, dst string, overwrite bool) error {
+	return v.transferProject(src, dst, overwrite, true)
+}
+
+// CopyProject duplicates every secret of a project into another project
+func (v *Vault) CopyProject(src, dst string, overwrite bool) error {
+	return v.transferProject(src, dst, overwrite, false)
+}
+
+// transfer copies or moves a single secret in one save
+func (v *Vault) transfer(srcProject, srcKey, dstProject, dstKey string, overwrite, move bool) error {
+	if !v.unlocked {
+		return ErrLocked
+	}
+
+	if srcProject == dstProject && srcKey == dstKey {
+		return fmt.Errorf("source and destination are the same: %s/%s", srcProject, srcKey)
+	}
+
+	return v.mutate(func(projects map[string]map[string]Secret) error {
+		secret, ok := projects[srcProject][srcKey]
+		if !ok {
+			return fmt.Errorf("secret %w: %s/%s", ErrNotFound, srcProject, srcKey)
+		}
+
+		if _, exists := projects[dstProject][dstKey]; exists && !overwrite {
+			return fmt.Errorf("secret %w: %s/%s", ErrExists, dstProject, dstKey)
+		}
+
+		if projects[dstProject] == nil {
+			projects[dstProject] = make(map[string]Secret)
+		}
+		projects[dstProject][dstKey] = secret.clone()
+
+		if move {
+			delete(projects[srcProject], srcKey)
+		}
+		return nil
+	})
+}
+
+// transferProject copies or moves every secret of a project in one save
+func (v *Vault) transferProject(src, dst string, overwrite, move bool) error {
+	if !v.unlocked {
+		return ErrLocked
+	}
+
+	if src == dst {
+		return fmt.Errorf("source and destination are the same: %s", src)
+	}
+
+	return v.mutate(func(projects map[string]map[string]Secret) error {
+		secrets, ok := projects[src]
+		if !ok {
+			return fmt.Errorf("project %w: %s", ErrNotFound, src)
+		}
+
+		if _, exists := projects[dst]; exists && !overwrite {
+			return fmt.Errorf("project %w: %s", ErrExists, dst)
+		}
+
+		if projects[dst] == nil {
+			projects[dst] = make(map[string]Secret)
+		}
+		for key, secret := range secrets {
+			projects[dst][key] = secret.clone()
+		}
+
+		if move {
+			delete(projects, src)
+		}
+		return nil
+	})
+}
+
+// mutate applies fn to a copy of the projects and saves the result.
+// The whole load-mutate-save cycle runs under the vault lock, starting from
+// the file as it is on disk, so concurrent uzp processes cannot drop each
+// other's changes. The in-memory data is only replaced once the save has
+// succeeded, so a failed mutation or write never leaves the vault partially
+// modified.
+func (v *Vault) mutate(fn func(projects map[string]map[string]Secret) error) error {
+	return v.withLock(func() error {
+		if err := v.reload(); err != nil {
+			return err
+		}
+
+		projects := cloneProjects(v.data.Projects)
+		if err := fn(projects); err != nil {
+			return err
+		}
+
+		previous := v.data.Projects
+		v.data.Projects = projects
+		if err := v.save(); err != nil {
+			v.data.Projects = previous
+			return err
+		}
+		return nil
+	})
+}
+
+// withLock runs fn while holding the inter-process vault lock
+func (v *Vault) withLock(fn func() error) (err error) {
+	lock, err := acquireLock(v.lockPath(), defaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if releaseErr := lock.release(); err == nil {
+			err = releaseErr
+		}
+	}()
+
+	return fn()
+}
+
+// reload re-reads the vault file with the current key, picking up changes
+// made by other processes since this vault was unlocked
+func (v *Vault) reload() error {
+	encVault, err := loadEncrypted(v.path)
+	if err != nil {
+		return err
+	}
+
+	if encVault.Salt != v.data.Salt || encVault.kdfParams() != v.data.KDF {
+		return fmt.Errorf("vault was re-keyed by another process, please retry")
+	}
+
+	vaultData, err := decryptVault(encVault, v.key)
+	if err != nil {
+		return err
+	}
+
+	v.data = vaultData
+	return nil
+}
+
+// decryptVault decrypts and decodes the data of an encrypted vault.
+// Header fields are taken from the file header rather than the payload.
+func decryptVault(encVault *EncryptedVault, key []byte) (*VaultData, error) {
+	if encVault.Version > formatVersion {
+		return nil, fmt.Errorf("vault format version %d is not supported by this version of uzp, please upgrade", encVault.Version)
+	}
+
+	encryptedData, err := base64.StdEncoding.DecodeString(encVault.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode encrypted data: %w", ErrCorrupt, err)
+	}
+
+	var decryptedData []byte
+	if encVault.Version >= 3 {
+		// The check block tells a wrong password from a modified header
+		if encVault.Check == "" {
+			return nil, ErrHeaderTampered
+		}
+		if !verifyKeyCheck(encVault.Check, key) {
+			return nil, ErrBadPassword
+		}
+
+		aad, err := headerAAD(encVault.Version, encVault.kdfParams(), encVault.Salt)
+		if err != nil {
+			return nil, err
+		}
+		if decryptedData, err = crypto.Decrypt(encryptedData, key, aad); err != nil {
+			// The key opened the check block, so the KDF and salt are genuine;
+			// only a modified version can still authenticate the data
+			if opensWithOtherVersion(encVault, encryptedData, key) {
+				return nil, ErrHeaderTampered
+			}
+			return nil, fmt.Errorf("%w: failed to decrypt vault data", ErrCorrupt)
+		}
+	} else {
+		// A check block only exists from version 3, so this is a downgrade
+		if encVault.Check != "" {
+			return nil, ErrHeaderTampered
+		}
+
+		// Successful authenticated decryption is what verifies the password
+		if decryptedData, err = crypto.Decrypt(encryptedData, key, nil); err != nil {
+			// A current vault with its version lowered and check block
+			// removed still opens under its real header
+			if opensWithOtherVersion(encVault, encryptedData, key) {
+				return nil, ErrHeaderTampered
+			}
+			return nil, ErrBadPassword
+		}
+	}
+
+	// Unmarshal vault data
+	var vaultData VaultData
+	if err := json.Unmarshal(decryptedData, &vaultData); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal vault data: %w", ErrCorrupt, err)
+	}
+	if vaultData.Projects == nil {
+		vaultData.Projects = make(map[string]map[string]Secret)
+	}
+	if vaultData.Version < 5 {
+		vaultData.HistoryLimit = DefaultHistoryLimit
+	}
+
+	vaultData.Salt = encVault.Salt
+	vaultData.KDF = encVault.kdfParams()
+	return &vaultData, nil
+}
+
+// kdfParams returns the KDF recorded in the header, or the fixed scrypt
+// parameters used by vaults written before it was recorded
+func (e *EncryptedVault) kdfParams() crypto.KDFParams {
+	if e.KDF == nil {
+		return crypto.LegacyKDFParams()
+	}
+	return *e.KDF
+}
+
+// ChangePassword re-encrypts the vault under a new master password,
+// keeping the current key derivation parameters
+func (v *Vault) ChangePassword(newPassword string) error {
+	if !v.unlocked {
+		return ErrLocked
+	}
+	return v.rekey(newPassword, v.data.KDF)
+}
+
+// UpgradeKDF re-derives the vault key with new KDF parameters.
+// The master password must match the one the vault was unlocked with.
+func (v *Vault) UpgradeKDF(masterPassword string, params crypto.KDFParams) error {
+	if !v.unlocked {
+		return ErrLocked
+	}
+
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	// Verify the password against the current key
+	salt, err := base64.StdEncoding.DecodeString(v.data.Salt)
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode salt: %w", ErrCorrupt, err)
+	}
+	currentKey, err := crypto.DeriveKey(masterPassword, salt, v.data.KDF)
+	if err != nil {
+		return err
+	}
+	match := subtle.ConstantTimeCompare(currentKey, v.key) == 1
+	for i := range currentKey {
+		currentKey[i] = 0
+	}
+	if !match {
+		return ErrBadPassword
+	}
+
+	return v.rekey(masterPassword, params)
+}
+
+// KDF returns the key derivation parameters of the unlocked vault
+func (v *Vault) KDF() (crypto.KDFParams, error) {
+	if !v.unlocked {
+		return crypto.KDFParams{}, ErrLocked
+	}
+	return v.data.KDF, nil
+}
+
+// rekey re-encrypts the vault with a key derived from password and params.
+// A fresh salt is generated, and the backup (still readable with the old
+// key) is removed once the new vault has been written.
+func (v *Vault) rekey(password string, params crypto.KDFParams) error {
+	// Generate salt
+	salt, err := crypto.GenerateSalt()
+	if err != nil {
+		return err
+	}
+
+	// Derive key from the new password
+	key, err := crypto.DeriveKey(password, salt, params)
+	if err != nil {
+		return err
+	}
+
+	return v.withLock(func() error {
+		if err := v.reload(); err != nil {
+			return err
+		}
+
+		previousData := *v.data
+		previousKey := v.key
+
+		v.data.Salt = base64.StdEncoding.EncodeToString(salt)
+		v.data.KDF = params
+		v.key = key
+
+		if err := v.save(); err != nil {
+			*v.data = previousData
+			v.key = previousKey
+			return err
+		}
+
+		// Clear the old key from memory
+		for i := range previousKey {
+			previousKey[i] = 0
+		}
+
+		if err := os.Remove(v.backupPath()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove vault backup: %w", err)
+		}
+		return nil
+	})
 }
 
 // Reset clears all vault data
 func (v *Vault) Reset() error {
 	if !v.unlocked {
-		return fmt.Errorf("vault is locked")
+		return ErrLocked
 	}
 
-	// Clear in-memory data
-	v.data.Projects = make(map[string]map[string]string)
-
-	// Save empty vault
-	if err := v.save(); err != nil {
+	// Clear all data and save empty vault
+	err := v.mutate(func(projects map[string]map[string]Secret) error {
+		for project := range projects {
+			delete(projects, project)
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
+	// The backup still holds the deleted secrets
+	if err := os.Remove(v.backupPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove vault backup: %w", err)
+	}
+
 	// Lock vault after reset
 	v.Lock()
 	return nil
@@ -257,26 +925,42 @@ func (v *Vault) Reset() error {
 // save saves the vault to disk
 func (v *Vault) save() error {
 	if !v.unlocked {
-		return fmt.Errorf("vault is locked")
+		return ErrLocked
 	}
 
+	// Drop projects that no longer hold any secrets
+	pruneEmptyProjects(v.data.Projects)
+
 	// Marshal vault data
 	jsonData, err := json.Marshal(v.data)
 	if err != nil {
 		return fmt.Errorf("failed to marshal vault data: %w", err)
 	}
 
-	// Encrypt data
-	encryptedData, err := crypto.Encrypt(jsonData, v.key)
+	// Encrypt data, binding the header to it
+	kdf := v.data.KDF
+	aad, err := headerAAD(formatVersion, kdf, v.data.Salt)
+	if err != nil {
+		return err
+	}
+
+	encryptedData, err := crypto.Encrypt(jsonData, v.key, aad)
 	if err != nil {
 		return fmt.Errorf("failed to encrypt vault: %w", err)
 	}
 
+	check, err := newKeyCheck(v.key)
+	if err != nil {
+		return err
+	}
+
 	// Create encrypted vault structure
 	encVault := EncryptedVault{
-		Salt: v.data.Salt,
-		Hash: v.data.Hash,
-		Data: base64.StdEncoding.EncodeToString(encryptedData),
+		Version: formatVersion,
+		KDF:     &kdf,
+		Salt:    v.data.Salt,
+		Check:   check,
+		Data:    base64.StdEncoding.EncodeToString(encryptedData),
 	}
 
 	// Marshal encrypted vault
@@ -285,25 +969,68 @@ func (v *Vault) save() error {
 		return fmt.Errorf("failed to marshal encrypted vault: %w", err)
 	}
 
+	// Keep the current file as the previous generation, but never let a
+	// corrupt primary overwrite a good backup
+	if current, err := os.ReadFile(v.path); err == nil && json.Valid(current) {
+		if err := writeFileAtomic(v.backupPath(), current, 0600); err != nil {
+			return fmt.Errorf("failed to write vault backup: %w", err)
+		}
+	}
+
 	// Write to file with proper permissions
-	return os.WriteFile(v.path, vaultJSON, 0600)
+	return writeFileAtomic(v.path, vaultJSON, 0600)
+}
+
+// lockPath returns the location of the inter-process lock file
+func (v *Vault) lockPath() string {
+	return v.path + ".lock"
+}
+
+// backupPath returns the location of the previous-generation vault file
+func (v *Vault) backupPath() string {
+	return v.path + ".bak"
 }
 
-// loadEncrypted loads the encrypted vault from disk
-func (v *Vault) loadEncrypted() (*EncryptedVault, error) {
-	data, err := os.ReadFile(v.path)
+// loadEncrypted loads an encrypted vault from disk
+func loadEncrypted(path string) (*EncryptedVault, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("vault %w at %s (run 'uzp init' first)", ErrNotFound, path)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read vault file: %w", err)
 	}
 
 	var encVault EncryptedVault
 	if err := json.Unmarshal(data, &encVault); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal encrypted vault: %w", err)
+		return nil, fmt.Errorf("%w: failed to unmarshal encrypted vault: %w", ErrCorrupt, err)
 	}
 
 	return &encVault, nil
 }
 
+// cloneProjects returns a deep copy of the project map
+func cloneProjects(projects map[string]map[string]Secret) map[string]map[string]Secret {
+	result := make(map[string]map[string]Secret, len(projects))
+	for project, secrets := range projects {
+		copied := make(map[string]Secret, len(secrets))
+		for k, secret := range secrets {
+			copied[k] = secret.clone()
+		}
+		result[project] = copied
+	}
+	return result
+}
+
+// pruneEmptyProjects removes projects without any secrets
+func pruneEmptyProjects(projects map[string]map[string]Secret) {
+	for project, secrets := range projects {
+		if len(secrets) == 0 {
+			delete(projects, project)
+		}
+	}
+}
+
 // contains checks if str contains substr (case-insensitive)
 func contains(str, substr string) bool {
 	return len(substr) > 0 && len(str) >= len(substr) &&
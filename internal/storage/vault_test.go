@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPassword = "correct horse battery"
+
+// newTestVault initializes an empty vault in a temporary directory
+func newTestVault(t *testing.T) *Vault {
+	t.Helper()
+	v := NewVaultAt(filepath.Join(t.TempDir(), "uzp.vault"))
+	if err := v.Initialize(testPassword); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	return v
+}
+
+// editVaultFile rewrites the header fields of the vault file at path
+func editVaultFile(t *testing.T, path string, edit func(fields map[string]interface{})) {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		t.Fatal(err)
+	}
+	edit(fields)
+
+	raw, err = json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// mustGet returns the value of a secret or fails the test
+func mustGet(t *testing.T, v *Vault, project, key string) string {
+	t.Helper()
+	value, err := v.Get(project, key)
+	if err != nil {
+		t.Fatalf("Get(%s/%s): %v", project, key, err)
+	}
+	return value
+}
+
+func TestUnlockRecoversTruncatedVault(t *testing.T) {
+	v := newTestVault(t)
+	if err := v.Add("app", "token", "one"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Add("app", "token", "two"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash mid-write without the atomic rename would leave a truncated file
+	raw, err := os.ReadFile(v.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(v.Path(), raw[:len(raw)/2], 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := NewVaultAt(v.Path())
+	if err := reopened.Unlock(testPassword); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if !reopened.Recovered() {
+		t.Error("Recovered() = false, want true")
+	}
+	if got := mustGet(t, reopened, "app", "token"); got != "one" {
+		t.Errorf("recovered value = %q, want %q", got, "one")
+	}
+
+	// The backup was restored as the primary file
+	if err := NewVaultAt(v.Path()).Unlock(testPassword); err != nil {
+		t.Errorf("Unlock after recovery: %v", err)
+	}
+
+	// The damaged file was moved aside, not overwritten
+	damaged, err := os.ReadFile(reopened.DamagedPath())
+	if err != nil {
+		t.Fatalf("read damaged vault: %v", err)
+	}
+	if !bytes.Equal(damaged, raw[:len(raw)/2]) {
+		t.Error("damaged vault file was not kept as it was")
+	}
+}
+
+func TestUnlockModifiedSaltKeepsPrimary(t *testing.T) {
+	v := newTestVault(t)
+	if err := v.Add("app", "token", "one"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Add("app", "token", "two"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A modified salt derives another key, which looks like a wrong
+	// password; the older backup must not replace the primary
+	editVaultFile(t, v.Path(), func(fields map[string]interface{}) {
+		salt, err := base64.StdEncoding.DecodeString(fields["salt"].(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		salt[0] ^= 0xff
+		fields["salt"] = base64.StdEncoding.EncodeToString(salt)
+	})
+	before, err := os.ReadFile(v.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := NewVaultAt(v.Path())
+	if err := reopened.Unlock(testPassword); !errors.Is(err, ErrBadPassword) {
+		t.Fatalf("Unlock = %v, want ErrBadPassword", err)
+	}
+	if reopened.Recovered() {
+		t.Error("Recovered() = true, want false")
+	}
+
+	after, err := os.ReadFile(v.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("vault file was changed by a failed unlock")
+	}
+}
+
+func TestSaveKeepsBackupOfPreviousGeneration(t *testing.T) {
+	v := newTestVault(t)
+	if err := v.Add("app", "token", "one"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Add("app", "token", "two"); err != nil {
+		t.Fatal(err)
+	}
+
+	backup := NewVaultAt(v.backupPath())
+	if err := backup.Unlock(testPassword); err != nil {
+		t.Fatalf("Unlock backup: %v", err)
+	}
+	if got := mustGet(t, backup, "app", "token"); got != "one" {
+		t.Errorf("backup value = %q, want %q", got, "one")
+	}
+
+	info, err := os.Stat(v.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("vault file mode = %o, want 600", perm)
+	}
+}
+
+func TestSaveDoesNotOverwriteBackupWithCorruptPrimary(t *testing.T) {
+	v := newTestVault(t)
+	if err := v.Add("app", "token", "one"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Add("app", "token", "two"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unlock recovers "one" from the backup; the next save must keep that
+	// generation as the backup rather than the garbage primary
+	if err := os.WriteFile(v.Path(), []byte("{garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	reopened := NewVaultAt(v.Path())
+	if err := reopened.Unlock(testPassword); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if err := reopened.Add("app", "other", "three"); err != nil {
+		t.Fatal(err)
+	}
+
+	backup := NewVaultAt(v.backupPath())
+	if err := backup.Unlock(testPassword); err != nil {
+		t.Fatalf("Unlock backup: %v", err)
+	}
+	if got := mustGet(t, backup, "app", "token"); got != "one" {
+		t.Errorf("backup value = %q, want %q", got, "one")
+	}
+}
@@ -0,0 +1,189 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported HMAC algorithms
+const (
+	AlgorithmSHA1   = "SHA1"
+	AlgorithmSHA256 = "SHA256"
+	AlgorithmSHA512 = "SHA512"
+)
+
+// Defaults of RFC 6238 and the otpauth URI format
+const (
+	DefaultAlgorithm = AlgorithmSHA1
+	DefaultDigits    = 6
+	DefaultPeriod    = 30 // Seconds
+)
+
+// Key is a TOTP shared secret with its parameters
+type Key struct {
+	Secret    []byte
+	Algorithm string
+	Digits    int
+	Period    int // Seconds
+
+	Issuer  string // From the otpauth URI, if any
+	Account string
+}
+
+// Parse reads an otpauth://totp/ URI or a bare base32 seed. Seeds may
+// contain spaces or dashes and use the default parameters.
+func Parse(s string) (*Key, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToLower(s), "otpauth://") {
+		return parseURI(s)
+	}
+
+	secret, err := decodeSeed(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{
+		Secret:    secret,
+		Algorithm: DefaultAlgorithm,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}, nil
+}
+
+// parseURI parses the Key URI Format used by authenticator apps:
+// otpauth://totp/Issuer:account?secret=...&algorithm=SHA1&digits=6&period=30
+func parseURI(s string) (*Key, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid otpauth URI: %w", err)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return nil, fmt.Errorf("unsupported otpauth type %q: only totp is supported", u.Host)
+	}
+
+	query := u.Query()
+	secret, err := decodeSeed(query.Get("secret"))
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{
+		Secret:    secret,
+		Algorithm: DefaultAlgorithm,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+		Issuer:    query.Get("issuer"),
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		if key.Issuer == "" {
+			key.Issuer = issuer
+		}
+		key.Account = strings.TrimSpace(account)
+	} else {
+		key.Account = label
+	}
+
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		key.Algorithm = strings.ToUpper(algorithm)
+		if _, err := key.hash(); err != nil {
+			return nil, err
+		}
+	}
+
+	if digits := query.Get("digits"); digits != "" {
+		n, err := strconv.Atoi(digits)
+		if err != nil || n < 6 || n > 8 {
+			return nil, fmt.Errorf("invalid digits %q: must be 6, 7 or 8", digits)
+		}
+		key.Digits = n
+	}
+
+	if period := query.Get("period"); period != "" {
+		n, err := strconv.Atoi(period)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid period %q: must be a positive number of seconds", period)
+		}
+		key.Period = n
+	}
+
+	return key, nil
+}
+
+// decodeSeed decodes a base32 seed, ignoring case, spaces, dashes and padding
+func decodeSeed(seed string) ([]byte, error) {
+	seed = strings.ToUpper(seed)
+	seed = strings.NewReplacer(" ", "", "-", "", "=", "").Replace(seed)
+	if seed == "" {
+		return nil, fmt.Errorf("missing TOTP secret")
+	}
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(seed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOTP secret: not base32")
+	}
+	return secret, nil
+}
+
+// hash returns the HMAC hash function of the key's algorithm
+func (k *Key) hash() (func() hash.Hash, error) {
+	switch k.Algorithm {
+	case AlgorithmSHA1:
+		return sha1.New, nil
+	case AlgorithmSHA256:
+		return sha256.New, nil
+	case AlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q: use SHA1, SHA256 or SHA512", k.Algorithm)
+	}
+}
+
+// Code returns the RFC 6238 code for time t
+func (k *Key) Code(t time.Time) (string, error) {
+	newHash, err := k.hash()
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix())/uint64(k.Period))
+
+	mac := hmac.New(newHash, k.Secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 section 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < k.Digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", k.Digits, value%modulo), nil
+}
+
+// Remaining returns how long the code for time t stays valid
+func (k *Key) Remaining(t time.Time) time.Duration {
+	period := int64(k.Period)
+	return time.Duration(period-t.Unix()%period) * time.Second
+}
+
+// Clear overwrites the shared secret in memory
+func (k *Key) Clear() {
+	for i := range k.Secret {
+		k.Secret[i] = 0
+	}
+}
@@ -0,0 +1,117 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 Appendix B test vectors (8 digits, 30 second period); each
+// algorithm uses the ASCII seed "1234567890" repeated to its key size
+func TestCodeRFC6238Vectors(t *testing.T) {
+	seeds := map[string]string{
+		AlgorithmSHA1:   "12345678901234567890",
+		AlgorithmSHA256: "12345678901234567890123456789012",
+		AlgorithmSHA512: "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+
+	tests := []struct {
+		unix                 int64
+		sha1, sha256, sha512 string
+	}{
+		{59, "94287082", "46119246", "90693936"},
+		{1111111109, "07081804", "68084774", "25091201"},
+		{1111111111, "14050471", "67062674", "99943326"},
+		{1234567890, "89005924", "91819424", "93441116"},
+		{2000000000, "69279037", "90698825", "38618901"},
+		{20000000000, "65353130", "77737706", "47863826"},
+	}
+
+	for _, tt := range tests {
+		for algorithm, want := range map[string]string{
+			AlgorithmSHA1:   tt.sha1,
+			AlgorithmSHA256: tt.sha256,
+			AlgorithmSHA512: tt.sha512,
+		} {
+			seed := base32.StdEncoding.EncodeToString([]byte(seeds[algorithm]))
+			key, err := Parse("otpauth://totp/Example:alice?secret=" + seed + "&algorithm=" + algorithm + "&digits=8")
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			got, err := key.Code(time.Unix(tt.unix, 0))
+			if err != nil {
+				t.Fatalf("Code: %v", err)
+			}
+			if got != want {
+				t.Errorf("%s at %d = %s, want %s", algorithm, tt.unix, got, want)
+			}
+		}
+	}
+}
+
+func TestParseSeed(t *testing.T) {
+	key, err := Parse(" jbsw y3dp-ehpk 3pxp ")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if string(key.Secret) != "Hello!\xde\xad\xbe\xef" {
+		t.Errorf("secret = %q", key.Secret)
+	}
+	if key.Algorithm != DefaultAlgorithm || key.Digits != DefaultDigits || key.Period != DefaultPeriod {
+		t.Errorf("parameters = %s/%d/%d, want the defaults", key.Algorithm, key.Digits, key.Period)
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	key, err := Parse("otpauth://totp/ACME%20Co:john@example.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME%20Co&algorithm=sha256&digits=8&period=60")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if key.Issuer != "ACME Co" || key.Account != "john@example.com" {
+		t.Errorf("label = %q:%q", key.Issuer, key.Account)
+	}
+	if key.Algorithm != AlgorithmSHA256 || key.Digits != 8 || key.Period != 60 {
+		t.Errorf("parameters = %s/%d/%d, want SHA256/8/60", key.Algorithm, key.Digits, key.Period)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"not base32!",
+		"otpauth://hotp/x?secret=JBSWY3DPEHPK3PXP&counter=1",
+		"otpauth://totp/x",
+		"otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&algorithm=MD5",
+		"otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&digits=5",
+		"otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&digits=9",
+		"otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&period=0",
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestCodeDigitsAndRemaining(t *testing.T) {
+	key, err := Parse("otpauth://totp/x?secret=JBSWY3DPEHPK3PXP&digits=6&period=30")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := key.Code(time.Unix(59, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != 6 || strings.Trim(code, "0123456789") != "" {
+		t.Errorf("code = %q, want 6 digits", code)
+	}
+
+	if got := key.Remaining(time.Unix(59, 0)); got != time.Second {
+		t.Errorf("Remaining at 59s = %s, want 1s", got)
+	}
+	if got := key.Remaining(time.Unix(60, 0)); got != 30*time.Second {
+		t.Errorf("Remaining at 60s = %s, want 30s", got)
+	}
+}
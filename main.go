@@ -1,15 +1,56 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/hungnguyen18/uzp-cli/cmd"
+	"github.com/hungnguyen18/uzp-cli/internal/storage"
+)
+
+// Exit codes that scripts can branch on
+const (
+	exitError       = 1 // Any other error
+	exitBadPassword = 2 // Wrong master password
+	exitLocked      = 3 // Vault locked and no password available
+	exitNotFound    = 4 // Secret, project, profile or vault not found
+	exitExists      = 5 // Secret, project, profile or vault already exists
+	exitCorrupt     = 6 // Vault file corrupt or header tampered
+	exitDue         = 7 // 'uzp due --check' found expired or soon-due secrets
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		var exitErr *cmd.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+
+		// The due report is already on stdout; only the status matters
+		if !errors.Is(err, cmd.ErrSecretsDue) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode maps an error to the documented process exit code
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrBadPassword):
+		return exitBadPassword
+	case errors.Is(err, storage.ErrLocked):
+		return exitLocked
+	case errors.Is(err, storage.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, storage.ErrExists):
+		return exitExists
+	case errors.Is(err, storage.ErrCorrupt), errors.Is(err, storage.ErrHeaderTampered):
+		return exitCorrupt
+	case errors.Is(err, cmd.ErrSecretsDue):
+		return exitDue
+	default:
+		return exitError
 	}
 }